@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"image"
+	"image/draw"
+	"log"
+	"os"
+
+	"github.com/daveagill/go-sdf/effects"
+	"github.com/daveagill/go-sdf/internal/imgutil"
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+func main() {
+	var (
+		stackPath string
+		padding   int
+	)
+
+	flag.StringVar(&stackPath, "stack", "", "Required. A JSON file listing the effects to apply, bottom first")
+	flag.IntVar(&padding, "padding", 0, "The transparent margin in pixels to add around the image, making room for effects beyond its edges")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] input.png output.png", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	outpath := flag.Arg(1)
+
+	if stackPath == "" {
+		log.Fatal("-stack not specified")
+	}
+	if padding < 0 {
+		log.Fatal("-padding must not be negative")
+	}
+
+	file, err := os.Open(stackPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	stack, err := effects.DecodeStack(file)
+	file.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := imgutil.Load(inpath)
+	size := src.Bounds().Size()
+	img := image.NewNRGBA(image.Rect(0, 0, size.X+2*padding, size.Y+2*padding))
+	draw.Draw(img, img.Bounds().Inset(padding), src, src.Bounds().Min, draw.Src)
+
+	// the anti-aliased transform keeps edges smooth, and limiting distances keeps a blank image
+	// finite
+	stencil := sdf.ImageAlphaStencil{Image: img, Alpha: sdf.HalfAlpha}
+	opts := sdf.Options{Algorithm: sdf.AntiAliased, MaxDistance: float64(img.Rect.Dx() + img.Rect.Dy())}
+	df, err := sdf.CalculateWithOptions(stencil, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// extend the colors of the edge outwards so that the anti-aliased edge takes its color
+	fill := imgutil.FillFromBoundaryPixels(img, df)
+	imgutil.SavePNG(outpath, effects.Render(df.SDF, fill, stack))
+}
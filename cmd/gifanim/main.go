@@ -1,10 +1,15 @@
 package main
 
 import (
+	"crypto/sha256"
 	"flag"
+	"fmt"
 	"image"
 	"image/color"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/daveagill/go-sdf/internal/imgutil"
 	"github.com/daveagill/go-sdf/sdf"
@@ -19,6 +24,7 @@ func main() {
 		frameDelay int
 		boomerang  bool
 		blackBg    bool
+		cacheDir   string
 	)
 
 	flag.StringVar(&startPath, "from", "", "Required. The start image (png/jpeg/gif)")
@@ -28,6 +34,7 @@ func main() {
 	flag.IntVar(&frameDelay, "framedelay", 0, "The delay between frames in 100ths of a second")
 	flag.BoolVar(&boomerang, "boomerang", true, "Whether to animate back to the initial image (Doubles the number of frames)")
 	flag.BoolVar(&blackBg, "blackbg", false, "Uses black as the background color instead of white")
+	flag.StringVar(&cacheDir, "cache", "", "Optional. A directory to keep computed fields in, so later runs with the same images reuse them")
 	flag.Parse()
 
 	if startPath == "" {
@@ -44,11 +51,12 @@ func main() {
 		log.Fatal("Images do not have the same dimensions")
 	}
 
-	startStencil := sdf.ImageAlphaStencil{Image: startImg, Alpha: sdf.HalfAlpha}
-	endStencil := sdf.ImageAlphaStencil{Image: endImg, Alpha: sdf.HalfAlpha}
+	// limit distances so that a blank image still has a finite field to morph from
+	size := startImg.Bounds().Size()
+	opts := sdf.Options{MaxDistance: float64(size.X + size.Y)}
 
-	startField := sdf.Calculate(startStencil)
-	endField := sdf.Calculate(endStencil)
+	startField := calculate(startPath, startImg, opts, cacheDir)
+	endField := calculate(endPath, endImg, opts, cacheDir)
 
 	blendedImg := &imgutil.BlendedImage{
 		From: imgutil.FillFromBoundaryPixels(startImg, startField),
@@ -65,8 +73,8 @@ func main() {
 	for i := 0; i < numFrames; i++ {
 		blendedImg.Ratio = float64(i) / float64(numFrames-1)
 		blendedSDF, _ := sdf.Lerp(startField.SDF, endField.SDF, blendedImg.Ratio)
-		stencil := sdf.ImplicitSurfaceStencil{SDF: blendedSDF}
-		frames[i] = sdf.DrawStencilImage(stencil, blendedImg, bgCol)
+		stencil := sdf.SmoothSurfaceStencil{SDF: blendedSDF}
+		frames[i] = sdf.DrawCoverageImage(stencil, blendedImg, bgCol)
 	}
 
 	// create the reverse sequence of frames to 'boomerang' back to the start
@@ -78,3 +86,46 @@ func main() {
 
 	imgutil.SaveGIF(outPath, frames, frameDelay)
 }
+
+// calculate returns the DisplacementField of an image, reusing the one kept in the cache
+// directory from an earlier run if there is one
+func calculate(path string, img image.Image, opts sdf.Options, cacheDir string) *sdf.DisplacementField {
+	var cachePath string
+	if cacheDir != "" {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		key := sha256.Sum256(append(contents, fmt.Sprint(opts.MaxDistance)...))
+		cachePath = filepath.Join(cacheDir, fmt.Sprintf("%x.sdfb", key))
+
+		// a missing or unreadable entry is simply recomputed
+		if data, err := ioutil.ReadFile(cachePath); err == nil {
+			var df sdf.DisplacementField
+			if err := df.UnmarshalBinary(data); err == nil {
+				return &df
+			}
+		}
+	}
+
+	stencil := sdf.ImageAlphaStencil{Image: img, Alpha: sdf.HalfAlpha}
+	df, err := sdf.CalculateWithOptions(stencil, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cachePath != "" {
+		data, err := df.MarshalBinaryWithOptions(sdf.MarshalOptions{Compress: true})
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(cachePath, data, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	return df
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"image"
+	"image/draw"
+	"log"
+	"math"
+	"os"
+
+	"github.com/daveagill/go-sdf/dxf"
+	"github.com/daveagill/go-sdf/internal/imgutil"
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+var units = map[string]dxf.Unit{
+	"mm": dxf.Millimetres,
+	"cm": dxf.Centimetres,
+	"in": dxf.Inches,
+}
+
+func main() {
+	var (
+		unitName  string
+		opts      dxf.Options
+		kerf      float64
+		side      string
+		tolerance float64
+	)
+
+	flag.StringVar(&unitName, "units", "mm", "The units of the drawing and of -kerf (mm/cm/in)")
+	flag.Float64Var(&opts.DPI, "dpi", 96, "The pixels per inch of the image")
+	flag.Float64Var(&kerf, "kerf", 0, "The width of the cut, which is offset by half of it from the edge of the shape")
+	flag.StringVar(&side, "side", "outside", "Which side of the edge to offset the cut to, so as to keep the shape or the hole it leaves to size (outside/inside)")
+	flag.Float64Var(&tolerance, "tolerance", 0.1, "How far in pixels the simplified outline may stray from the traced outline (0 to keep every point)")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] input.png output.dxf", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	outpath := flag.Arg(1)
+
+	unit, ok := units[unitName]
+	if !ok {
+		log.Fatalf("Unknown -units %q", unitName)
+	}
+	opts.Units = unit
+	if !(opts.DPI > 0) {
+		log.Fatal("-dpi must be positive")
+	}
+	if kerf < 0 {
+		log.Fatal("-kerf must not be negative")
+	}
+	if tolerance < 0 {
+		log.Fatal("-tolerance must not be negative")
+	}
+
+	// the cut runs along the iso-line half a kerf outside or inside the edge
+	offset := opts.ToPixels(kerf) / 2
+	switch side {
+	case "outside":
+	case "inside":
+		offset = -offset
+	default:
+		log.Fatalf("Unknown -side %q", side)
+	}
+
+	// a margin around the image makes room for the cut to pass outside shapes at its edges
+	src := imgutil.Load(inpath)
+	size := src.Bounds().Size()
+	margin := int(math.Ceil(math.Max(0, offset))) + 1
+	img := image.NewNRGBA(image.Rect(0, 0, size.X+2*margin, size.Y+2*margin))
+	draw.Draw(img, img.Bounds().Inset(margin), src, src.Bounds().Min, draw.Src)
+
+	// the anti-aliased transform places the edge between pixels from their alpha, so the cut
+	// follows the shape rather than the staircase of its pixels
+	stencil := sdf.ImageAlphaStencil{Image: img, Alpha: sdf.HalfAlpha}
+	field, err := sdf.CalculateWithOptions(stencil, sdf.Options{Algorithm: sdf.AntiAliased})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var lines []vector.Polyline
+	for _, line := range vector.Trace(field.SDF, offset) {
+		for i, v := range line {
+			line[i] = v.Sub(vector.Vec{X: float64(margin), Y: float64(margin)})
+		}
+		if tolerance > 0 {
+			line = line.SimplifyDouglasPeucker(tolerance)
+		}
+		if len(line) >= 3 {
+			lines = append(lines, line)
+		}
+	}
+
+	file, err := os.Create(outpath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts.Height = float64(size.Y)
+	if err := dxf.Write(file, lines, opts); err != nil {
+		log.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,23 +1,167 @@
 package main
 
 import (
+	"flag"
+	"io"
 	"log"
 	"os"
 
 	"github.com/daveagill/go-sdf/internal/imgutil"
 	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/sdfio"
 )
 
+var algorithms = map[string]sdf.Algorithm{
+	"edt":           sdf.ExactEDT,
+	"bruteforce":    sdf.BruteForce,
+	"deadreckoning": sdf.DeadReckoning,
+	"jfa":           sdf.JumpFlooding,
+	"aa":            sdf.AntiAliased,
+}
+
+var textureFormats = map[string]sdfio.TextureFormat{
+	"r8":   sdfio.R8,
+	"r16f": sdfio.R16F,
+	"r32f": sdfio.R32F,
+	"bc4":  sdfio.BC4,
+}
+
+var boundaryModes = map[string]sdf.BoundaryMode{
+	"pixel": sdf.PixelBoundary,
+	"edge":  sdf.EdgeBoundary,
+}
+
 func main() {
-	if len(os.Args) != 3 {
-		log.Fatalf("2 arguments expected: %s input.png sdf_output.png", os.Args[0])
+	var (
+		algorithmName string
+		boundaryName  string
+		encoding      sdf.Encoding
+		format        string
+		textureName   string
+	)
+
+	flag.StringVar(&algorithmName, "algorithm", "edt", "The distance transform to use (edt/bruteforce/deadreckoning/jfa/aa)")
+	flag.StringVar(&boundaryName, "boundary", "pixel", "Where the boundary lies, on the edge pixels or between pixels (pixel/edge)")
+	flag.Float64Var(&encoding.Spread, "spread", 0, "The distance in pixels either side of the edge to encode in the full range of gray (0 keeps the fixed range of [-127, 128])")
+	flag.Float64Var(&encoding.ZeroLevel, "zero", 0.5, "The gray level of the edge in range [0, 1], used with -spread")
+	flag.BoolVar(&encoding.Invert, "invert", false, "Draw the inside bright and the outside dark, used with -spread")
+	flag.IntVar(&encoding.BitDepth, "depth", 8, "The bits per pixel of the output (8/16), used with -spread")
+	flag.StringVar(&format, "format", "png", "The output file format, a gray image or distances as float32 (png/pfm/raw/npy/exr/ktx2/dds)")
+	flag.StringVar(&textureName, "texture", "bc4", "The pixel format of -format=ktx2 or dds, where r8 and bc4 are gray levels and r16f and r32f are distances (r8/r16f/r32f/bc4)")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] input.png sdf_output.png", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	outpath := flag.Arg(1)
+
+	algorithm, ok := algorithms[algorithmName]
+	if !ok {
+		log.Fatalf("Unknown -algorithm %q", algorithmName)
+	}
+
+	boundary, ok := boundaryModes[boundaryName]
+	if !ok {
+		log.Fatalf("Unknown -boundary %q", boundaryName)
+	}
+
+	if format != "ktx2" && format != "dds" {
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "texture" {
+				log.Fatalf("-%s only applies to -format=ktx2 or dds", f.Name)
+			}
+		})
+	}
+
+	switch format {
+	case "png", "ktx2", "dds":
+		if format != "png" {
+			flag.Visit(func(f *flag.Flag) {
+				if f.Name == "depth" {
+					log.Fatalf("-%s only applies to -format=png", f.Name)
+				}
+			})
+		}
+		if encoding.Spread == 0 {
+			flag.Visit(func(f *flag.Flag) {
+				if f.Name == "zero" || f.Name == "invert" || f.Name == "depth" {
+					log.Fatalf("-%s requires -spread", f.Name)
+				}
+			})
+		} else if err := encoding.Validate(); err != nil {
+			log.Fatal(err)
+		}
+	case "pfm", "raw", "npy", "exr":
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "spread" || f.Name == "zero" || f.Name == "invert" || f.Name == "depth" {
+				log.Fatalf("-%s only applies to -format=png", f.Name)
+			}
+		})
+	default:
+		log.Fatalf("Unknown -format %q", format)
+	}
+
+	texture, ok := textureFormats[textureName]
+	if !ok {
+		log.Fatalf("Unknown -texture %q", textureName)
 	}
-	inpath := os.Args[1]
-	outpath := os.Args[2]
 
 	img := imgutil.Load(inpath)
 	stencil := sdf.ImageAlphaStencil{Image: img, Alpha: sdf.HalfAlpha}
-	field := sdf.Calculate(stencil)
-	grayImg := field.Draw()
-	imgutil.SavePNG(outpath, grayImg)
+	field, err := sdf.CalculateWithOptions(stencil, sdf.Options{Algorithm: algorithm, Boundary: boundary})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch {
+	case format == "pfm":
+		save(outpath, func(w io.Writer) error {
+			return sdfio.WritePFM(w, field.SDF)
+		})
+	case format == "raw":
+		save(outpath, func(w io.Writer) error {
+			return sdfio.WriteRaw(w, field.SDF)
+		})
+	case format == "npy":
+		save(outpath, func(w io.Writer) error {
+			return sdfio.WriteNPY(w, field.SDF, sdfio.Float32)
+		})
+	case format == "exr":
+		save(outpath, func(w io.Writer) error {
+			return sdfio.WriteEXR(w, field.SDF, sdfio.Float32, sdfio.EXRZip)
+		})
+	case format == "ktx2" || format == "dds":
+		// without -spread the texture uses the same fixed range as Draw
+		if encoding.Spread == 0 {
+			encoding = sdf.DrawEncoding
+		}
+		options := sdfio.TextureOptions{Format: texture, Encoding: encoding}
+		save(outpath, func(w io.Writer) error {
+			if format == "dds" {
+				return sdfio.WriteDDS(w, field.SDF, options)
+			}
+			return sdfio.WriteKTX2(w, field.SDF, options)
+		})
+	case encoding.Spread == 0:
+		imgutil.SavePNG(outpath, field.Draw())
+	default:
+		save(outpath, func(w io.Writer) error {
+			return sdfio.WritePNG(w, field.SDF, encoding)
+		})
+	}
+}
+
+// save writes a file using the given function
+func save(path string, write func(w io.Writer) error) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := write(f); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
 }
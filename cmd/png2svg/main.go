@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/daveagill/go-sdf/internal/imgutil"
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/svg"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+var simplifiers = map[string]func(vector.Polyline, float64) vector.Polyline{
+	"dp":   vector.Polyline.SimplifyDouglasPeucker,
+	"vw":   vector.Polyline.SimplifyVisvalingam,
+	"none": func(p vector.Polyline, _ float64) vector.Polyline { return p },
+}
+
+func main() {
+	var (
+		simplifyName string
+		tolerance    float64
+		curves       bool
+	)
+
+	flag.StringVar(&simplifyName, "simplify", "dp", "How to simplify the traced outline, by Douglas-Peucker or Visvalingam-Whyatt (dp/vw/none)")
+	flag.Float64Var(&tolerance, "tolerance", 0.5, "How far in pixels the simplified outline and curves may stray from the traced outline")
+	flag.BoolVar(&curves, "curves", false, "Fit cubic Bézier curves to the simplified outline")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] input.png output.svg", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	outpath := flag.Arg(1)
+
+	simplify, ok := simplifiers[simplifyName]
+	if !ok {
+		log.Fatalf("Unknown -simplify %q", simplifyName)
+	}
+	if !(tolerance > 0) {
+		log.Fatal("-tolerance must be positive")
+	}
+
+	// the anti-aliased transform places the edge between pixels from their alpha, so the
+	// traced outline follows the shape rather than the staircase of its pixels
+	img := imgutil.Load(inpath)
+	size := img.Bounds().Size()
+	stencil := sdf.ImageAlphaStencil{Image: img, Alpha: sdf.HalfAlpha}
+	field, err := sdf.CalculateWithOptions(stencil, sdf.Options{Algorithm: sdf.AntiAliased})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	shape := &vector.Shape{FillRule: vector.EvenOdd}
+	for _, line := range vector.Trace(field.SDF, 0) {
+		line = simplify(line, tolerance)
+		if len(line) < 3 {
+			continue
+		}
+		if curves {
+			shape.Contours = append(shape.Contours, line.FitCubics(tolerance))
+		} else {
+			shape.Contours = append(shape.Contours, line.Contour())
+		}
+	}
+
+	file, err := os.Create(outpath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	doc := &svg.Document{ViewBox: [4]float64{0, 0, float64(size.X), float64(size.Y)}, Paths: []*vector.Shape{shape}}
+	if err := svg.Encode(file, doc); err != nil {
+		log.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/daveagill/go-sdf/internal/imgutil"
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+var filters = map[string]sdf.Filter{
+	"bilinear": sdf.Bilinear,
+	"bicubic":  sdf.Bicubic,
+}
+
+func main() {
+	var (
+		encoding   sdf.Encoding
+		scale      float64
+		filterName string
+		width      float64
+	)
+
+	flag.Float64Var(&encoding.Spread, "spread", 0, "The -spread the SDF was drawn with (0 for the fixed range of [-127, 128])")
+	flag.Float64Var(&encoding.ZeroLevel, "zero", 0.5, "The gray level of the edge in range [0, 1], used with -spread")
+	flag.BoolVar(&encoding.Invert, "invert", false, "The inside was drawn bright and the outside dark, used with -spread")
+	flag.Float64Var(&scale, "scale", 1, "The scale to render the mask at")
+	flag.StringVar(&filterName, "filter", "bilinear", "How to interpolate the SDF when scaling (bilinear/bicubic)")
+	flag.Float64Var(&width, "width", 1, "The width in output pixels of the anti-aliased edge")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] sdf.png mask_output.png", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	outpath := flag.Arg(1)
+
+	filter, ok := filters[filterName]
+	if !ok {
+		log.Fatalf("Unknown -filter %q", filterName)
+	}
+	if !(scale > 0) {
+		log.Fatal("-scale must be positive")
+	}
+	if !(width > 0) {
+		log.Fatal("-width must be positive")
+	}
+	if encoding.Spread == 0 {
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "zero" || f.Name == "invert" {
+				log.Fatalf("-%s requires -spread", f.Name)
+			}
+		})
+		encoding = sdf.DrawEncoding
+	}
+
+	field, err := sdf.Decode(imgutil.Load(inpath), encoding)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	imgutil.SavePNG(outpath, field.Render(sdf.RenderOptions{Scale: scale, Width: width, Sampler: sdf.Sampler{Filter: filter}}))
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/daveagill/go-sdf/internal/imgutil"
+	"github.com/daveagill/go-sdf/msdf"
+	"github.com/daveagill/go-sdf/sdfio"
+	"github.com/daveagill/go-sdf/svg"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+func main() {
+	var (
+		width      int
+		height     int
+		spread     float64
+		renderPath string
+		scale      float64
+	)
+
+	flag.IntVar(&width, "width", 32, "The width of the MSDF in pixels")
+	flag.IntVar(&height, "height", 32, "The height of the MSDF in pixels")
+	flag.Float64Var(&spread, "range", 4, "The distance in pixels either side of the edge that the MSDF encodes")
+	flag.StringVar(&renderPath, "render", "", "Optional. Also renders the MSDF to this png as a preview")
+	flag.Float64Var(&scale, "scale", 8, "The scale to render the preview at")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] shape.txt|shape.svg msdf_output.png", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	outpath := flag.Arg(1)
+
+	shape, err := load(inpath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fitted, err := fit(shape, width, height, spread)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	field := msdf.Generate(fitted, width, height)
+	if strings.EqualFold(filepath.Ext(outpath), ".exr") {
+		saveEXR(outpath, field)
+	} else {
+		imgutil.SavePNG(outpath, field.Draw(spread))
+	}
+
+	if renderPath != "" {
+		imgutil.SavePNG(renderPath, field.Render(scale))
+	}
+}
+
+// load reads either an SVG document or a shape description, depending on the file extension
+func load(path string) (*vector.Shape, error) {
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		doc, err := svg.Decode(file)
+		if err != nil {
+			return nil, err
+		}
+		// msdf colors the edges of a single set of contours
+		return doc.Shape().Merge(), nil
+	}
+
+	desc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return vector.ParseShape(string(desc))
+}
+
+// saveEXR writes the distances of each channel of the MSDF to an OpenEXR image
+func saveEXR(path string, field *msdf.MSDF) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	channels := []sdfio.Channel{{Name: "R", Field: field.R}, {Name: "G", Field: field.G}, {Name: "B", Field: field.B}}
+	if err := sdfio.WriteEXRChannels(f, channels, sdfio.Half, sdfio.EXRZip); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// fit scales and centres the shape to fill the given size, less a margin on every side
+func fit(shape *vector.Shape, w, h int, margin float64) (*vector.Shape, error) {
+	min, max := shape.Bounds()
+	size := max.Sub(min)
+	if !(size.X > 0 && size.Y > 0) {
+		return nil, errors.New("shape is empty or has no area to fit to the MSDF")
+	}
+	if !(float64(w)-2*margin > 0 && float64(h)-2*margin > 0) {
+		return nil, fmt.Errorf("a %vx%v MSDF has no room inside a range of %v on every side", w, h, margin)
+	}
+
+	s := math.Min((float64(w)-2*margin)/size.X, (float64(h)-2*margin)/size.Y)
+	offset := vector.Vec{
+		X: (float64(w) - size.X*s) / 2,
+		Y: (float64(h) - size.Y*s) / 2,
+	}
+
+	return shape.Transform(func(v vector.Vec) vector.Vec {
+		return v.Sub(min).Scale(s).Add(offset)
+	}), nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/daveagill/go-sdf/internal/imgutil"
+	"github.com/daveagill/go-sdf/svg"
+)
+
+func main() {
+	var (
+		width  int
+		height int
+	)
+
+	flag.IntVar(&width, "width", 256, "The width of the SDF in pixels")
+	flag.IntVar(&height, "height", 256, "The height of the SDF in pixels")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] input.svg sdf_output.png", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	outpath := flag.Arg(1)
+
+	file, err := os.Open(inpath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	doc, err := svg.Decode(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// distances are measured to the vector outline itself so are exact at any resolution
+	field := doc.Fit(width, height).Field(width, height)
+	imgutil.SavePNG(outpath, field.Draw())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/daveagill/go-sdf/internal/imgutil"
+	"github.com/daveagill/go-sdf/ttf"
+)
+
+// printable ASCII
+const defaultChars = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+func main() {
+	var (
+		size    float64
+		padding int
+		spread  float64
+		width   int
+		chars   string
+	)
+
+	flag.Float64Var(&size, "size", 32, "The font size in pixels per em")
+	flag.IntVar(&padding, "padding", 4, "The pixels of field kept around each glyph")
+	flag.Float64Var(&spread, "spread", 4, "The distance in pixels either side of the edge that the atlas encodes")
+	flag.IntVar(&width, "width", 512, "The width of the atlas in pixels")
+	flag.StringVar(&chars, "chars", defaultChars, "The characters to include")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		log.Fatalf("2 arguments expected: %s [flags] font.ttf atlas_output_prefix", os.Args[0])
+	}
+	inpath := flag.Arg(0)
+	prefix := flag.Arg(1)
+
+	data, err := ioutil.ReadFile(inpath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	font, err := ttf.Parse(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	atlas, err := ttf.NewAtlas(font, ttf.AtlasOptions{
+		Size:    size,
+		Padding: padding,
+		Spread:  spread,
+		Width:   width,
+		Runes:   []rune(chars),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	imgutil.SavePNG(prefix+".png", atlas.Image())
+
+	save := func(path string, write func(f *os.File) error) {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := write(f); err != nil {
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	save(prefix+".json", func(f *os.File) error {
+		return atlas.WriteJSON(f)
+	})
+
+	face := strings.TrimSuffix(filepath.Base(inpath), filepath.Ext(inpath))
+	save(prefix+".fnt", func(f *os.File) error {
+		return atlas.WriteBMFont(f, face, filepath.Base(prefix)+".png")
+	})
+}
@@ -0,0 +1,128 @@
+// Package dxf writes closed polylines, such as those traced from a Signed-Distance-Field, as
+// AutoCAD DXF drawings for CAD software and laser cutters.
+package dxf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Unit is the unit of length of a drawing
+type Unit int
+
+const (
+	// Millimetres is the default
+	Millimetres Unit = iota
+	Centimetres
+	Inches
+)
+
+// perInch is the number of each Unit in an inch
+var perInch = map[Unit]float64{
+	Millimetres: 25.4,
+	Centimetres: 2.54,
+	Inches:      1,
+}
+
+// Options controls how Write maps polylines from pixels into a drawing
+type Options struct {
+	Units Unit
+	// DPI is the number of pixels per inch. Zero uses 96.
+	DPI float64
+	// Height is the height in pixels of the image the polylines were traced from. Images have y
+	// pointing down whereas drawings have it pointing up, so y is flipped about half this height.
+	Height float64
+}
+
+// ToUnits returns a length in pixels in the units of the drawing
+func (o Options) ToUnits(px float64) float64 {
+	dpi := o.DPI
+	if dpi == 0 {
+		dpi = 96
+	}
+	return px / dpi * perInch[o.Units]
+}
+
+// ToPixels returns a length in the units of the drawing in pixels
+func (o Options) ToPixels(length float64) float64 {
+	return length / o.ToUnits(1)
+}
+
+// Write writes the polylines as a DXF drawing of closed POLYLINE entities on layer 0, scaled
+// from pixels into the units of the drawing. The drawing is a minimal AutoCAD R12 (AC1009) file of
+// just a HEADER section and an ENTITIES section, which every DXF reader accepts. R12 has no
+// header variable for the units of a drawing, so $MEASUREMENT gives only whether they are metric
+// or imperial and readers must be told the units when importing.
+//
+// Flipping y reverses the direction of travel, so the outer boundaries traced by vector.Trace run
+// anticlockwise in the drawing and the boundaries of holes clockwise, as is usual in CAD.
+func Write(w io.Writer, lines []vector.Polyline, o Options) error {
+	if _, ok := perInch[o.Units]; !ok {
+		return fmt.Errorf("unknown unit %v", o.Units)
+	}
+	if o.DPI < 0 || math.IsNaN(o.DPI) || math.IsInf(o.DPI, 0) {
+		return fmt.Errorf("invalid DPI %v", o.DPI)
+	}
+
+	bw := bufio.NewWriter(w)
+	pair := func(code int, value string) {
+		fmt.Fprintf(bw, "%3d\n%s\n", code, value)
+	}
+	point := func(v vector.Vec) {
+		pair(10, number(o.ToUnits(v.X)))
+		pair(20, number(o.ToUnits(o.Height-v.Y)))
+	}
+
+	measurement := "1"
+	if o.Units == Inches {
+		measurement = "0"
+	}
+	pair(0, "SECTION")
+	pair(2, "HEADER")
+	pair(9, "$ACADVER")
+	pair(1, "AC1009")
+	pair(9, "$MEASUREMENT")
+	pair(70, measurement)
+	pair(0, "ENDSEC")
+
+	pair(0, "SECTION")
+	pair(2, "ENTITIES")
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		// the vertices follow the POLYLINE entity and are ended by a SEQEND entity
+		pair(0, "POLYLINE")
+		pair(8, "0")
+		pair(66, "1")
+		point(vector.Vec{X: 0, Y: o.Height})
+		pair(70, "1")
+		for _, v := range line {
+			pair(0, "VERTEX")
+			pair(8, "0")
+			point(v)
+		}
+		pair(0, "SEQEND")
+		pair(8, "0")
+	}
+	pair(0, "ENDSEC")
+	pair(0, "EOF")
+
+	return bw.Flush()
+}
+
+// number formats a coordinate to at most 6 decimal places
+func number(v float64) string {
+	v = math.Round(v*1e6) / 1e6
+	if v == 0 {
+		// avoid writing negative zero
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,137 @@
+package dxf
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// groups returns the group code and value pairs of a DXF file
+func groups(t *testing.T, s string) [][2]string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if len(lines)%2 != 0 {
+		t.Fatalf("DXF should have an even number of lines, not %v", len(lines))
+	}
+	var pairs [][2]string
+	for i := 0; i < len(lines); i += 2 {
+		pairs = append(pairs, [2]string{strings.TrimSpace(lines[i]), lines[i+1]})
+	}
+	return pairs
+}
+
+var square = vector.Polyline{{X: 0, Y: 0}, {X: 96, Y: 0}, {X: 96, Y: 48}, {X: 0, Y: 48}}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, []vector.Polyline{square}, Options{Units: Millimetres, Height: 48}); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	pairs := groups(t, buf.String())
+
+	if last := pairs[len(pairs)-1]; last != [2]string{"0", "EOF"} {
+		t.Errorf("DXF should end with EOF, not %v", last)
+	}
+
+	// only the coordinates of vertices are collected, not those of the POLYLINE itself
+	var entities []string
+	var xs, ys []float64
+	for _, p := range pairs {
+		switch p[0] {
+		case "0":
+			entities = append(entities, p[1])
+		case "10", "20":
+			if entities[len(entities)-1] != "VERTEX" {
+				continue
+			}
+			v, err := strconv.ParseFloat(p[1], 64)
+			if err != nil {
+				t.Fatalf("Coordinate should be a number, not %q", p[1])
+			}
+			if p[0] == "10" {
+				xs = append(xs, v)
+			} else {
+				ys = append(ys, v)
+			}
+		}
+	}
+
+	expected := "SECTION ENDSEC SECTION POLYLINE VERTEX VERTEX VERTEX VERTEX SEQEND ENDSEC EOF"
+	if s := strings.Join(entities, " "); s != expected {
+		t.Errorf("Structure should be %v, not %v", expected, s)
+	}
+
+	// 96 pixels at 96 DPI is an inch, and y is flipped to point up
+	expectedXs := []float64{0, 25.4, 25.4, 0}
+	expectedYs := []float64{12.7, 12.7, 0, 0}
+	if len(xs) != 4 || len(ys) != 4 {
+		t.Fatalf("There should be 4 vertices, not %v", len(xs))
+	}
+	for i := range expectedXs {
+		if math.Abs(xs[i]-expectedXs[i]) > 1e-9 || math.Abs(ys[i]-expectedYs[i]) > 1e-9 {
+			t.Errorf("Vertex %v should be (%v, %v), not (%v, %v)", i, expectedXs[i], expectedYs[i], xs[i], ys[i])
+		}
+	}
+}
+
+func TestWriteHeader(t *testing.T) {
+	for _, test := range []struct {
+		units       Unit
+		measurement string
+	}{
+		{Millimetres, "1"},
+		{Centimetres, "1"},
+		{Inches, "0"},
+	} {
+		var buf bytes.Buffer
+		if err := Write(&buf, nil, Options{Units: test.units}); err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		pairs := groups(t, buf.String())
+
+		// the header is every pair between the opening of the HEADER section and its end
+		var header [][2]string
+		for _, p := range pairs[2:] {
+			if p == [2]string{"0", "ENDSEC"} {
+				break
+			}
+			header = append(header, p)
+		}
+
+		expected := [][2]string{
+			{"9", "$ACADVER"}, {"1", "AC1009"},
+			{"9", "$MEASUREMENT"}, {"70", test.measurement},
+		}
+		if pairs[1] != [2]string{"2", "HEADER"} || !reflect.DeepEqual(header, expected) {
+			t.Errorf("Header of a drawing in %v should be %v, not %v", test.units, expected, header)
+		}
+	}
+}
+
+func TestWriteDPI(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, []vector.Polyline{square}, Options{Units: Inches, DPI: 48}); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if !strings.Contains(buf.String(), " 10\n2\n") {
+		t.Errorf("96 pixels at 48 DPI should be 2 inches")
+	}
+}
+
+func TestOptionsUnits(t *testing.T) {
+	o := Options{Units: Millimetres, DPI: 254}
+	if mm := o.ToUnits(10); math.Abs(mm-1) > 1e-9 {
+		t.Errorf("10 pixels at 254 DPI should be 1mm, not %v", mm)
+	}
+	if px := o.ToPixels(0.5); math.Abs(px-5) > 1e-9 {
+		t.Errorf("0.5mm at 254 DPI should be 5 pixels, not %v", px)
+	}
+
+	if err := Write(&bytes.Buffer{}, nil, Options{Units: Unit(9)}); err == nil {
+		t.Errorf("Unknown unit should be an error")
+	}
+}
@@ -0,0 +1,88 @@
+package effects
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// Bevel lights the shape as though it were raised from the page, with Highlight on slopes that
+// face the light and Shadow on slopes that face away.
+//
+// A bevel slopes up over Size pixels inside the edge and is placed Within the shape. An emboss
+// instead slopes up over Size pixels either side of the edge, so that it also lights the page
+// around the shape, and is placed Above the fill.
+type Bevel struct {
+	Size float64
+	// Depth scales the steepness of the slopes. Zero uses a depth of 1.
+	Depth float64
+	// Angle is the direction the light comes from, in degrees anticlockwise from the right
+	Angle float64
+	// Altitude is the height of the light above the page, in degrees. Zero uses 30 degrees. A
+	// light directly overhead, at 90 degrees, faces the flat ground as squarely as any slope so
+	// shades nothing.
+	Altitude  float64
+	Highlight color.Color
+	Shadow    color.Color
+	Emboss    bool
+}
+
+// gradientSampler measures the slope of the field at pixel centres, where the derivative of its
+// bicubic filter is the central difference of the neighbouring pixels
+var gradientSampler = sdf.Sampler{Filter: sdf.Bicubic}
+
+// Paint implements Effect
+func (b Bevel) Paint(field *sdf.SDF, x, y int) color.RGBA64 {
+	depth, altitude := b.Depth, b.Altitude
+	if depth == 0 {
+		depth = 1
+	}
+	if altitude == 0 {
+		altitude = 30
+	}
+
+	// the height of the surface rises with distance from its foot, over the width of the slope
+	d := field.At(x, y)
+	foot, width := 0.0, b.Size
+	if b.Emboss {
+		foot, width = b.Size, 2*b.Size
+	}
+	t := (foot - d) / width
+	if !(t > 0 && t < 1) {
+		return color.RGBA64{}
+	}
+	slope := -depth * 6 * t * (1 - t) / width
+
+	// the surface normal tilts against the gradient of its height
+	gx, gy := gradientSampler.Gradient(field, float64(x)+0.5, float64(y)+0.5)
+	if math.IsNaN(gx) || math.IsInf(gx, 0) || math.IsNaN(gy) || math.IsInf(gy, 0) {
+		// a field with no boundary holds infinite distances and has no slope
+		gx, gy = 0, 0
+	}
+	nx, ny, nz := -slope*gx, -slope*gy, 1.0
+	n := math.Sqrt(nx*nx + ny*ny + nz*nz)
+
+	// y points down the image, so the light's direction is flipped vertically
+	a, alt := b.Angle*math.Pi/180, altitude*math.Pi/180
+	lx, ly, lz := math.Cos(alt)*math.Cos(a), -math.Cos(alt)*math.Sin(a), math.Sin(alt)
+	if 1-lz < 1e-9 {
+		return color.RGBA64{}
+	}
+
+	// shade is 0 on flat ground and 1 where the surface faces the light directly
+	shade := ((nx*lx+ny*ly+nz*lz)/n - lz) / (1 - lz)
+
+	if shade > 0 {
+		return paint(b.Highlight, shade)
+	}
+	return paint(b.Shadow, -shade)
+}
+
+// Placement implements Effect
+func (b Bevel) Placement() Placement {
+	if b.Emboss {
+		return Above
+	}
+	return Within
+}
@@ -0,0 +1,137 @@
+// Package effects renders a shape with layered effects, such as outlines, glows, drop shadows
+// and bevels, drawn cheaply from the Signed-Distance-Field of the shape.
+//
+// Distances are measured in pixels of the field, negative inside the shape and positive
+// outside, and every edge is anti-aliased over one pixel.
+package effects
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// Placement selects where an effect is painted relative to the shape's fill
+type Placement int
+
+const (
+	// Below paints the effect beneath the fill
+	Below Placement = iota
+	// Within paints the effect over the fill and clips it to the shape, along with the fill
+	Within
+	// Above paints the effect over the fill and everything beneath it
+	Above
+)
+
+// Effect is one layer of an effect stack
+type Effect interface {
+	// Paint returns the alpha-premultiplied color that the effect paints over the pixel at (x, y).
+	// Effects placed Within the shape need not clip themselves to it.
+	Paint(field *sdf.SDF, x, y int) color.RGBA64
+	// Placement returns where the effect is painted relative to the fill
+	Placement() Placement
+}
+
+// Render returns an image the size of the field in which the shape is filled from the fill image
+// and decorated with the stack of effects. Effects placed Below the fill, such as DropShadow and
+// OuterGlow, are painted first in the order of the stack. Then the fill is painted along with the
+// effects placed Within the shape, and then the effects placed Above it.
+//
+// The fill image only gives the color of the shape, as the field alone decides where the shape
+// covers, so its alpha is ignored. Use image.NewUniform to fill with a single color.
+func Render(field *sdf.SDF, fill image.Image, stack []Effect) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, field.Width, field.Height))
+	origin := fill.Bounds().Min
+
+	for y := 0; y < field.Height; y++ {
+		for x := 0; x < field.Width; x++ {
+			var c pixel
+			for _, e := range stack {
+				if e.Placement() == Below {
+					c = c.over(e.Paint(field, x, y))
+				}
+			}
+
+			// effects within the shape are clipped to it along with the fill, so that its edge
+			// is anti-aliased only once
+			shape := pixel{}.over(paint(opaque(fill.At(origin.X+x, origin.Y+y)), 1))
+			for _, e := range stack {
+				if e.Placement() == Within {
+					shape = shape.over(e.Paint(field, x, y))
+				}
+			}
+			c = c.over(shape.scale(inside(field.At(x, y))).rgba64())
+
+			for _, e := range stack {
+				if e.Placement() == Above {
+					c = c.over(e.Paint(field, x, y))
+				}
+			}
+			img.SetRGBA64(x, y, c.rgba64())
+		}
+	}
+
+	return img
+}
+
+// pixel is an alpha-premultiplied color with channels in range [0, 1]
+type pixel struct {
+	r, g, b, a float64
+}
+
+// over composites c over p
+func (p pixel) over(c color.RGBA64) pixel {
+	const max = math.MaxUint16
+	a := float64(c.A) / max
+	return pixel{
+		float64(c.R)/max + p.r*(1-a),
+		float64(c.G)/max + p.g*(1-a),
+		float64(c.B)/max + p.b*(1-a),
+		a + p.a*(1-a),
+	}
+}
+
+// scale returns p with its coverage scaled by k
+func (p pixel) scale(k float64) pixel {
+	return pixel{p.r * k, p.g * k, p.b * k, p.a * k}
+}
+
+func (p pixel) rgba64() color.RGBA64 {
+	channel := func(v float64) uint16 {
+		return uint16(math.Round(math.Max(0, math.Min(1, v)) * math.MaxUint16))
+	}
+	return color.RGBA64{channel(p.r), channel(p.g), channel(p.b), channel(p.a)}
+}
+
+// paint returns the color c with its alpha scaled by coverage in range [0, 1]
+func paint(c color.Color, coverage float64) color.RGBA64 {
+	if c == nil || !(coverage > 0) {
+		return color.RGBA64{}
+	}
+	coverage = math.Min(1, coverage)
+	r, g, b, a := c.RGBA()
+	scale := func(v uint32) uint16 {
+		return uint16(math.Round(float64(v) * coverage))
+	}
+	return color.RGBA64{scale(r), scale(g), scale(b), scale(a)}
+}
+
+// opaque returns the color c with full alpha
+func opaque(c color.Color) color.Color {
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return color.Black
+	}
+	unpremultiply := func(v uint32) uint16 {
+		return uint16(v * math.MaxUint16 / a)
+	}
+	return color.RGBA64{unpremultiply(r), unpremultiply(g), unpremultiply(b), math.MaxUint16}
+}
+
+// inside returns the coverage of a pixel whose centre lies at distance d from an edge, ramping
+// from 1 to 0 over the pixel either side of the edge
+func inside(d float64) float64 {
+	return sdf.Coverage(d, 1)
+}
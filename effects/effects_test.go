@@ -0,0 +1,196 @@
+package effects
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// edgeField returns a field of a vertical edge at x=edge, with the shape to its left
+func edgeField(w, h int, edge float64) *sdf.SDF {
+	f := sdf.New(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			f.Set(x, y, float64(x)+0.5-edge)
+		}
+	}
+	return f
+}
+
+// alphaAt returns the alpha of a single effect painted at a pixel, in range [0, 1]
+func alphaAt(e Effect, f *sdf.SDF, x, y int) float64 {
+	return float64(e.Paint(f, x, y).A) / math.MaxUint16
+}
+
+var (
+	red   = color.NRGBA{255, 0, 0, 255}
+	white = image.NewUniform(color.White)
+)
+
+func TestRenderFill(t *testing.T) {
+	img := Render(edgeField(4, 1, 2), image.NewUniform(color.NRGBA{0, 0, 255, 128}), nil)
+
+	// the fill's alpha is ignored and the edge pixels are anti-aliased
+	for x, expected := range []color.RGBA{{0, 0, 255, 255}, {0, 0, 255, 255}, {0, 0, 0, 0}, {0, 0, 0, 0}} {
+		if c := img.RGBAAt(x, 0); c != expected {
+			t.Errorf("Pixel %v should be %v, not %v", x, expected, c)
+		}
+	}
+}
+
+func TestRenderOrder(t *testing.T) {
+	f := edgeField(6, 1, 3)
+	glow := OuterGlow{Size: 10, Color: red}
+	outline := Outline{Position: Inside, Width: 2, Color: color.Black}
+
+	// the glow is painted beneath the fill wherever it is in the stack, and the outline above
+	img := Render(f, white, []Effect{outline, glow})
+	if c := img.RGBAAt(0, 0); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("Fill should cover the glow, not be %v", c)
+	}
+	if c := img.RGBAAt(2, 0); c != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("Outline should cover the fill, not be %v", c)
+	}
+	if c := img.RGBAAt(4, 0); c.R < 128 || c.G != 0 || c.B != 0 {
+		t.Errorf("Glow should show outside the shape, not be %v", c)
+	}
+}
+
+func TestOutline(t *testing.T) {
+	f := edgeField(20, 1, 10)
+	for _, test := range []struct {
+		position OutlinePosition
+		width    float64
+		outlined []int
+		filled   []int
+		clear    []int
+	}{
+		{Outside, 3, []int{10, 11, 12}, []int{8, 9}, []int{13, 14}},
+		{Inside, 3, []int{7, 8, 9}, []int{5, 6}, []int{10, 11}},
+		{Centred, 4, []int{8, 9, 10, 11}, []int{6}, []int{13}},
+	} {
+		img := Render(f, white, []Effect{Outline{Position: test.position, Width: test.width, Color: red}})
+		for _, x := range test.outlined {
+			if c := img.RGBAAt(x, 0); c != (color.RGBA{255, 0, 0, 255}) {
+				t.Errorf("Outline %v should cover pixel %v, not leave it %v", test.position, x, c)
+			}
+		}
+		for _, x := range test.filled {
+			if c := img.RGBAAt(x, 0); c != (color.RGBA{255, 255, 255, 255}) {
+				t.Errorf("Outline %v should leave pixel %v filled, not %v", test.position, x, c)
+			}
+		}
+		for _, x := range test.clear {
+			if c := img.RGBAAt(x, 0); c.A != 0 {
+				t.Errorf("Outline %v should leave pixel %v clear, not %v", test.position, x, c)
+			}
+		}
+	}
+}
+
+func TestOutlineMeetsFill(t *testing.T) {
+	f := edgeField(8, 1, 3.3)
+
+	// an outside outline beneath the fill leaves no gap at the edge
+	img := Render(f, white, []Effect{Outline{Position: Outside, Width: 2, Color: color.Black}})
+	for x := 0; x < 5; x++ {
+		if a := img.RGBAAt(x, 0).A; a != 255 {
+			t.Errorf("Pixel %v should be opaque, not %v", x, a)
+		}
+	}
+
+	// an inside outline is anti-aliased along with the fill, so it leaves the edge as it was
+	plain := Render(f, white, nil)
+	img = Render(f, white, []Effect{Outline{Position: Inside, Width: 2, Color: color.Black}})
+	if a, expected := img.RGBAAt(3, 0).A, plain.RGBAAt(3, 0).A; a != expected {
+		t.Errorf("Edge pixel should have alpha %v, not %v", expected, a)
+	}
+}
+
+func TestGlows(t *testing.T) {
+	f := edgeField(20, 1, 10)
+
+	outer := OuterGlow{Size: 4, Color: red}
+	if a := alphaAt(outer, f, 5, 0); a != 1 {
+		t.Errorf("Outer glow should be full inside the shape, not %v", a)
+	}
+	if a0, a1 := alphaAt(outer, f, 10, 0), alphaAt(outer, f, 12, 0); !(a0 > a1 && a1 > 0) {
+		t.Errorf("Outer glow should fade away from the shape, not be %v then %v", a0, a1)
+	}
+	if a := alphaAt(outer, f, 15, 0); a != 0 {
+		t.Errorf("Outer glow should end %v pixels from the shape, not be %v", outer.Size, a)
+	}
+
+	inner := InnerGlow{Size: 4, Color: red}
+	if a0, a1 := alphaAt(inner, f, 9, 0), alphaAt(inner, f, 7, 0); !(a0 > a1 && a1 > 0) {
+		t.Errorf("Inner glow should fade away from the edge, not be %v then %v", a0, a1)
+	}
+	if a := alphaAt(inner, f, 4, 0); a != 0 {
+		t.Errorf("Inner glow should end %v pixels inside the edge, not be %v", inner.Size, a)
+	}
+	if p := inner.Placement(); p != Within {
+		t.Errorf("Inner glow should be clipped within the shape, not placed %v", p)
+	}
+}
+
+func TestDropShadow(t *testing.T) {
+	f := edgeField(20, 3, 10)
+
+	hard := DropShadow{DX: 3, DY: 1, Color: color.Black}
+	if a := alphaAt(hard, f, 12, 1); a != 1 {
+		t.Errorf("Shadow should cover pixels moved 3 pixels out, not %v", a)
+	}
+	if a := alphaAt(hard, f, 14, 1); a != 0 {
+		t.Errorf("Shadow should end 3 pixels out, not be %v", a)
+	}
+
+	soft := DropShadow{DX: 3, DY: 1, Softness: 4, Color: color.Black}
+	if a := alphaAt(soft, f, 14, 1); !(a > 0 && a < 0.5) {
+		t.Errorf("Soft shadow should fade out beyond its edge, not be %v", a)
+	}
+
+	// beyond the edge of the field there is no shape to cast a shadow
+	if a := alphaAt(DropShadow{DX: 3, Color: color.Black}, edgeField(5, 1, 10), 1, 0); a != 0 {
+		t.Errorf("Shadow should not come from beyond the field, not be %v", a)
+	}
+}
+
+func TestBevel(t *testing.T) {
+	f := edgeField(20, 1, 10)
+
+	// the slope inside the edge faces right, towards a light from the right
+	lit := Bevel{Size: 4, Angle: 0, Highlight: color.White, Shadow: color.Black}
+	c := lit.Paint(f, 8, 0)
+	if c.A == 0 || c.R != c.A {
+		t.Errorf("Slope facing the light should be highlighted, not %v", c)
+	}
+
+	unlit := Bevel{Size: 4, Angle: 180, Highlight: color.White, Shadow: color.Black}
+	c = unlit.Paint(f, 8, 0)
+	if c.A == 0 || c.R != 0 {
+		t.Errorf("Slope facing away from the light should be shadowed, not %v", c)
+	}
+
+	if a := alphaAt(lit, f, 3, 0); a != 0 {
+		t.Errorf("Flat top of the bevel should be unlit, not %v", a)
+	}
+	if a := alphaAt(lit, f, 12, 0); a != 0 {
+		t.Errorf("Bevel should not light outside the shape, not %v", a)
+	}
+
+	emboss := Bevel{Size: 4, Angle: 0, Highlight: color.White, Shadow: color.Black, Emboss: true}
+	if a := alphaAt(emboss, f, 12, 0); a == 0 {
+		t.Errorf("Emboss should light outside the shape")
+	}
+
+	// the flat ground faces a light overhead as squarely as any slope, so nothing is shaded
+	overhead := Bevel{Size: 4, Altitude: 90, Highlight: color.White, Shadow: color.Black}
+	for x := 0; x < 20; x++ {
+		if c := overhead.Paint(f, x, 0); c != (color.RGBA64{}) {
+			t.Errorf("Bevel lit from overhead should not shade pixel %v, not %v", x, c)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package effects
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// layer is the JSON form of every kind of Effect, told apart by its type
+type layer struct {
+	Type      string     `json:"type"`
+	Color     string     `json:"color"`
+	Position  string     `json:"position"`
+	Width     float64    `json:"width"`
+	Size      float64    `json:"size"`
+	Offset    [2]float64 `json:"offset"`
+	Softness  float64    `json:"softness"`
+	Depth     float64    `json:"depth"`
+	Angle     float64    `json:"angle"`
+	Altitude  float64    `json:"altitude"`
+	Highlight string     `json:"highlight"`
+	Shadow    string     `json:"shadow"`
+	Emboss    bool       `json:"emboss"`
+}
+
+var outlinePositions = map[string]OutlinePosition{
+	"":         Outside,
+	"outside":  Outside,
+	"inside":   Inside,
+	"centred":  Centred,
+	"centered": Centred,
+}
+
+// DecodeStack reads an effect stack from a JSON array of layers, each with a "type" and the
+// fields of that type of Effect:
+//
+//	[
+//	  {"type": "dropShadow", "offset": [3, 3], "softness": 4, "color": "#00000080"},
+//	  {"type": "outerGlow", "size": 8, "color": "#ffcc00"},
+//	  {"type": "innerGlow", "size": 4, "color": "#ffffff80"},
+//	  {"type": "bevel", "size": 4, "depth": 1, "angle": 135, "altitude": 30,
+//	   "highlight": "#ffffffc0", "shadow": "#00000080", "emboss": false},
+//	  {"type": "outline", "position": "outside", "width": 2, "color": "#000"}
+//	]
+//
+// Colors are written in hex as #rgb, #rgba, #rrggbb or #rrggbbaa, and outline positions as
+// outside, inside or centred. A bevel's highlight defaults to white and its shadow to black.
+func DecodeStack(r io.Reader) ([]Effect, error) {
+	var layers []layer
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&layers); err != nil {
+		return nil, err
+	}
+
+	stack := make([]Effect, len(layers))
+	for i, l := range layers {
+		effect, err := l.effect()
+		if err != nil {
+			return nil, fmt.Errorf("effect %v: %v", i, err)
+		}
+		stack[i] = effect
+	}
+	return stack, nil
+}
+
+func (l layer) effect() (Effect, error) {
+	switch l.Type {
+	case "outline", "outerGlow", "innerGlow", "dropShadow":
+	case "bevel":
+		return l.bevel()
+	default:
+		return nil, fmt.Errorf("unknown effect type %q", l.Type)
+	}
+
+	c, err := parseColor(l.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	switch l.Type {
+	case "outerGlow":
+		return OuterGlow{Size: l.Size, Color: c}, nil
+	case "innerGlow":
+		return InnerGlow{Size: l.Size, Color: c}, nil
+	case "dropShadow":
+		return DropShadow{DX: l.Offset[0], DY: l.Offset[1], Softness: l.Softness, Color: c}, nil
+	}
+
+	position, ok := outlinePositions[l.Position]
+	if !ok {
+		return nil, fmt.Errorf("unknown outline position %q", l.Position)
+	}
+	return Outline{Position: position, Width: l.Width, Color: c}, nil
+}
+
+func (l layer) bevel() (Effect, error) {
+	if l.Highlight == "" {
+		l.Highlight = "#fff"
+	}
+	if l.Shadow == "" {
+		l.Shadow = "#000"
+	}
+	highlight, err := parseColor(l.Highlight)
+	if err != nil {
+		return nil, err
+	}
+	shadow, err := parseColor(l.Shadow)
+	if err != nil {
+		return nil, err
+	}
+	return Bevel{
+		Size:      l.Size,
+		Depth:     l.Depth,
+		Angle:     l.Angle,
+		Altitude:  l.Altitude,
+		Highlight: highlight,
+		Shadow:    shadow,
+		Emboss:    l.Emboss,
+	}, nil
+}
+
+// parseColor parses a hex color of the form #rgb, #rgba, #rrggbb or #rrggbbaa
+func parseColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 || len(hex) == 4 {
+		var long strings.Builder
+		for _, c := range hex {
+			long.WriteRune(c)
+			long.WriteRune(c)
+		}
+		hex = long.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if !strings.HasPrefix(s, "#") || len(hex) != 8 || err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
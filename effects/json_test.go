@@ -0,0 +1,54 @@
+package effects
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestDecodeStack(t *testing.T) {
+	stack, err := DecodeStack(strings.NewReader(`[
+		{"type": "dropShadow", "offset": [3, -2], "softness": 4, "color": "#00000080"},
+		{"type": "outerGlow", "size": 8, "color": "#fc0"},
+		{"type": "innerGlow", "size": 4, "color": "#ffffff80"},
+		{"type": "bevel", "size": 4, "angle": 135, "highlight": "#ffffffc0", "emboss": true},
+		{"type": "outline", "position": "centred", "width": 2, "color": "#123a"}
+	]`))
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	expected := []Effect{
+		DropShadow{DX: 3, DY: -2, Softness: 4, Color: color.NRGBA{0, 0, 0, 0x80}},
+		OuterGlow{Size: 8, Color: color.NRGBA{0xff, 0xcc, 0, 0xff}},
+		InnerGlow{Size: 4, Color: color.NRGBA{0xff, 0xff, 0xff, 0x80}},
+		Bevel{Size: 4, Angle: 135, Highlight: color.NRGBA{0xff, 0xff, 0xff, 0xc0}, Shadow: color.NRGBA{0, 0, 0, 0xff}, Emboss: true},
+		Outline{Position: Centred, Width: 2, Color: color.NRGBA{0x11, 0x22, 0x33, 0xaa}},
+	}
+	if len(stack) != len(expected) {
+		t.Fatalf("Stack should have %v effects, not %v", len(expected), len(stack))
+	}
+	for i := range expected {
+		if stack[i] != expected[i] {
+			t.Errorf("Effect %v should be %+v, not %+v", i, expected[i], stack[i])
+		}
+	}
+}
+
+func TestDecodeStackErrors(t *testing.T) {
+	for _, stack := range []string{
+		`{}`,
+		`[{"type": "sparkle", "color": "#fff"}]`,
+		`[{"type": "outline", "width": 2}]`,
+		`[{"type": "outline", "color": "#ff"}]`,
+		`[{"type": "outline", "color": "fff"}]`,
+		`[{"type": "outline", "color": "#ggg"}]`,
+		`[{"type": "outline", "color": "#fff", "position": "above"}]`,
+		`[{"type": "outline", "color": "#fff", "colour": "#000"}]`,
+		`[{"type": "bevel", "shadow": "black"}]`,
+	} {
+		if _, err := DecodeStack(strings.NewReader(stack)); err == nil {
+			t.Errorf("Decoding %v should return an error", stack)
+		}
+	}
+}
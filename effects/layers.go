@@ -0,0 +1,110 @@
+package effects
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// OutlinePosition selects where an Outline lies relative to the edge of the shape
+type OutlinePosition int
+
+const (
+	// Outside places the outline outside the shape, growing it
+	Outside OutlinePosition = iota
+	// Inside places the outline inside the shape, covering its fill
+	Inside
+	// Centred centres the outline on the edge of the shape
+	Centred
+)
+
+// Outline strokes the edge of the shape with a band of color Width pixels wide. An outline
+// Outside the shape is placed Below the fill, one Inside is placed Within the shape and a Centred
+// one is placed Above the fill.
+type Outline struct {
+	Position OutlinePosition
+	Width    float64
+	Color    color.Color
+}
+
+// Paint implements Effect
+func (o Outline) Paint(field *sdf.SDF, x, y int) color.RGBA64 {
+	// the band is what lies inside its outer edge but not inside its inner edge, where the fill
+	// covers the outer edge of an inside outline and the inner edge of an outside outline
+	d := field.At(x, y)
+	switch o.Position {
+	case Inside:
+		return paint(o.Color, 1-inside(d+o.Width))
+	case Centred:
+		return paint(o.Color, inside(d-o.Width/2)-inside(d+o.Width/2))
+	}
+	return paint(o.Color, inside(d-o.Width))
+}
+
+// Placement implements Effect
+func (o Outline) Placement() Placement {
+	switch o.Position {
+	case Inside:
+		return Within
+	case Centred:
+		return Above
+	}
+	return Below
+}
+
+// OuterGlow surrounds the shape with color that fades away over Size pixels
+type OuterGlow struct {
+	Size  float64
+	Color color.Color
+}
+
+// Paint implements Effect
+func (g OuterGlow) Paint(field *sdf.SDF, x, y int) color.RGBA64 {
+	return paint(g.Color, sdf.Coverage(field.At(x, y)-g.Size/2, g.Size))
+}
+
+// Placement implements Effect. Outer glows are placed Below the fill.
+func (g OuterGlow) Placement() Placement {
+	return Below
+}
+
+// InnerGlow lights the inside of the shape with color that fades away over Size pixels from the
+// edge
+type InnerGlow struct {
+	Size  float64
+	Color color.Color
+}
+
+// Paint implements Effect
+func (g InnerGlow) Paint(field *sdf.SDF, x, y int) color.RGBA64 {
+	return paint(g.Color, sdf.Coverage(-field.At(x, y)-g.Size/2, g.Size))
+}
+
+// Placement implements Effect. Inner glows are placed Within the shape.
+func (g InnerGlow) Placement() Placement {
+	return Within
+}
+
+// DropShadow casts a shadow of the shape, moved by the offset (DX, DY) and blurred so that its
+// edge softens over Softness pixels
+type DropShadow struct {
+	DX, DY   float64
+	Softness float64
+	Color    color.Color
+}
+
+// Paint implements Effect
+func (s DropShadow) Paint(field *sdf.SDF, x, y int) color.RGBA64 {
+	// everything beyond the edges of the field is far outside the shape
+	sampler := sdf.Sampler{Border: sdf.Constant, Constant: float64(field.Width + field.Height)}
+	d := sampler.Sample(field, float64(x)+0.5-s.DX, float64(y)+0.5-s.DY)
+
+	width := math.Max(0, s.Softness) + 1
+	return paint(s.Color, sdf.Coverage(d, width))
+}
+
+// Placement implements Effect. Drop shadows are placed Below the fill.
+func (s DropShadow) Placement() Placement {
+	return Below
+}
@@ -79,16 +79,16 @@ func SaveGIF(path string, frames []image.Image, delay int) {
 
 // FillFromBoundaryPixels returns an image where off-surface pixels are sourced from
 // the nearest boundary pixels. Effectively extruding the boundary pixels out to the
-// borders of the image.
+// borders of the image. If there are no boundary pixels then the image is returned unchanged.
 func FillFromBoundaryPixels(img image.Image, df *sdf.DisplacementField) image.Image {
 	outImg := image.NewRGBA(image.Rect(0, 0, df.Width, df.Height))
 
 	for y := 0; y < df.Height; y++ {
 		for x := 0; x < df.Width; x++ {
-			if df.At(x, y) < 0 {
+			boundaryX, boundaryY, ok := df.LookupNearestBoundary(x, y)
+			if df.At(x, y) < 0 || !ok {
 				outImg.Set(x, y, img.At(x, y))
 			} else {
-				boundaryX, boundaryY := df.NearestBoundaryAt(x, y)
 				outImg.Set(x, y, img.At(boundaryX, boundaryY))
 			}
 		}
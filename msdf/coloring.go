@@ -0,0 +1,143 @@
+package msdf
+
+import (
+	"math"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Color is a set of the red, green and blue channels that an edge contributes to
+type Color uint8
+
+// The channel sets that edges are coloured with
+const (
+	Black   Color = 0
+	Red     Color = 1
+	Green   Color = 2
+	Yellow  Color = Red | Green
+	Blue    Color = 4
+	Magenta Color = Red | Blue
+	Cyan    Color = Green | Blue
+	White   Color = Red | Green | Blue
+)
+
+// Edge is a segment of a contour together with the channels it contributes to
+type Edge struct {
+	vector.Segment
+	Color Color
+}
+
+// ColorEdges assigns channels to the edges of every contour of a shape such that the two edges
+// meeting at any corner share only one channel. A corner is where the direction of the contour
+// turns by more than angleThreshold radians (3 radians is a typical choice).
+//
+// This is the simple edge colouring strategy of msdfgen. Contours with no corners are white,
+// contours with one corner are split into three differently coloured runs, and all other
+// contours cycle through cyan, magenta and yellow, switching colour at each corner.
+func ColorEdges(shape *vector.Shape, angleThreshold float64) [][]Edge {
+	crossThreshold := math.Sin(angleThreshold)
+	contours := make([][]Edge, len(shape.Contours))
+
+	for i, c := range shape.Contours {
+		seed := uint64(0)
+		contours[i] = colorContour(c, crossThreshold, &seed)
+	}
+
+	return contours
+}
+
+func colorContour(c vector.Contour, crossThreshold float64, seed *uint64) []Edge {
+	corners := []int{}
+	for i, seg := range c {
+		prev := c[(i+len(c)-1)%len(c)]
+		if isCorner(prev.Direction(1).Normalize(), seg.Direction(0).Normalize(), crossThreshold) {
+			corners = append(corners, i)
+		}
+	}
+
+	edges := make([]Edge, len(c))
+	for i, seg := range c {
+		edges[i] = Edge{seg, White}
+	}
+
+	switch len(corners) {
+	case 0:
+		// smooth contours can be white as there are no corners to preserve
+		return edges
+
+	case 1:
+		// a teardrop shape needs three colours so the single corner is surrounded by different ones
+		colors := [3]Color{}
+		colors[0] = switchColor(White, seed, Black)
+		colors[1] = White
+		colors[2] = switchColor(colors[0], seed, Black)
+
+		corner := corners[0]
+		edges = append(edges[corner:], edges[:corner]...)
+		if len(edges) < 3 {
+			edges = splitInThirds(edges)
+		}
+
+		m := len(edges)
+		for i := range edges {
+			edges[i].Color = colors[int(3+2.875*float64(i)/float64(m-1)-1.4375+0.5)-2]
+		}
+		return edges
+	}
+
+	// otherwise switch colour at each corner, taking care that the last run differs from the first
+	color := switchColor(White, seed, Black)
+	initial := color
+	spline := 0
+	start := corners[0]
+	m := len(edges)
+	for i := 0; i < m; i++ {
+		index := (start + i) % m
+		if spline+1 < len(corners) && corners[spline+1] == index {
+			spline++
+			banned := Black
+			if spline == len(corners)-1 {
+				banned = initial
+			}
+			color = switchColor(color, seed, banned)
+		}
+		edges[index].Color = color
+	}
+
+	return edges
+}
+
+// isCorner predicates whether the unit directions a and b, either side of a vertex, form a corner
+func isCorner(a, b vector.Vec, crossThreshold float64) bool {
+	return a.Dot(b) <= 0 || math.Abs(a.Cross(b)) > crossThreshold
+}
+
+// switchColor returns the next colour to use after the given one, which must not be the banned colour
+func switchColor(color Color, seed *uint64, banned Color) Color {
+	combined := color & banned
+	if combined == Red || combined == Green || combined == Blue {
+		return combined ^ White
+	}
+
+	if color == Black || color == White {
+		start := [3]Color{Cyan, Magenta, Yellow}
+		next := start[*seed%3]
+		*seed /= 3
+		return next
+	}
+
+	shifted := color << (1 + (*seed & 1))
+	*seed >>= 1
+	return (shifted | shifted>>3) & White
+}
+
+// splitInThirds splits every edge in three so that there are enough edges to colour
+func splitInThirds(edges []Edge) []Edge {
+	ret := []Edge{}
+	for _, e := range edges {
+		a, rest := e.Split(1.0 / 3)
+		b, c := rest.Split(0.5)
+		ret = append(ret, Edge{a, e.Color}, Edge{b, e.Color}, Edge{c, e.Color})
+	}
+	return ret
+}
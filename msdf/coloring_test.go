@@ -0,0 +1,75 @@
+package msdf
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+func square(x0, y0, x1, y1 float64) vector.Contour {
+	return vector.Contour{
+		vector.Line{P0: vector.Vec{X: x0, Y: y0}, P1: vector.Vec{X: x1, Y: y0}},
+		vector.Line{P0: vector.Vec{X: x1, Y: y0}, P1: vector.Vec{X: x1, Y: y1}},
+		vector.Line{P0: vector.Vec{X: x1, Y: y1}, P1: vector.Vec{X: x0, Y: y1}},
+		vector.Line{P0: vector.Vec{X: x0, Y: y1}, P1: vector.Vec{X: x0, Y: y0}},
+	}
+}
+
+func mustParse(t *testing.T, desc string) *vector.Shape {
+	t.Helper()
+	s, err := vector.ParseShape(desc)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	return s
+}
+
+func TestColorEdgesSmoothContour(t *testing.T) {
+	circle := mustParse(t, "{ 10, 5; (10, 10); 5, 10; (0, 10); 0, 5; (0, 0); 5, 0; (10, 0); # }")
+	contours := ColorEdges(circle, 3)
+
+	for i, e := range contours[0] {
+		if e.Color != White {
+			t.Errorf("Edge %v of a smooth contour should be White, not %v", i, e.Color)
+		}
+	}
+}
+
+func TestColorEdgesCorners(t *testing.T) {
+	for _, c := range []vector.Contour{square(0, 0, 10, 10), square(0, 0, 10, 10)[:3]} {
+		shape := &vector.Shape{Contours: []vector.Contour{c}}
+		edges := ColorEdges(shape, 3)[0]
+
+		for i, e := range edges {
+			prev := edges[(i+len(edges)-1)%len(edges)]
+			if shared := bits.OnesCount8(uint8(prev.Color & e.Color)); shared != 1 {
+				t.Errorf("Edges meeting at corner %v of a %v-sided polygon should share exactly one channel, not %v (%v and %v)",
+					i, len(c), shared, prev.Color, e.Color)
+			}
+		}
+	}
+}
+
+func TestColorEdgesTeardrop(t *testing.T) {
+	// a single curve looping back on itself has only one corner
+	teardrop := mustParse(t, "{ 0, 0; (20, -10; 20, 10); # }")
+	edges := ColorEdges(teardrop, 3)[0]
+
+	if len(edges) != 3 {
+		t.Fatalf("Teardrop with one edge should be split into 3 edges, not %v", len(edges))
+	}
+
+	first, last := edges[0].Color, edges[len(edges)-1].Color
+	if bits.OnesCount8(uint8(first&last)) != 1 {
+		t.Errorf("Edges either side of the teardrop corner should share exactly one channel, not %v and %v", first, last)
+	}
+
+	seen := map[Color]bool{}
+	for _, e := range edges {
+		seen[e.Color] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Teardrop should be coloured with 3 different colours, not %v", len(seen))
+	}
+}
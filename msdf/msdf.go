@@ -0,0 +1,213 @@
+// Package msdf generates Multi-channel Signed-Distance-Fields (MSDFs) from vector shapes.
+//
+// A single-channel SDF rounds off sharp corners when it is magnified because bilinear
+// interpolation of the distances cannot represent the intersection of two edges. An MSDF stores
+// three distance fields, one per channel, where the edges meeting at each corner contribute to
+// different channels. The median of the three interpolated channels then reproduces the corner.
+//
+// This follows the approach of Viktor Chlumský's msdfgen.
+package msdf
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// MSDF is a Multi-channel Signed-Distance-Field made of a red, green and blue channel field.
+// Like an SDF, distances are negative inside the shape and positive outside.
+type MSDF struct {
+	R, G, B *sdf.SDF
+	Width   int
+	Height  int
+}
+
+// New returns a zeroed MSDF of the given size
+func New(w, h int) *MSDF {
+	return &MSDF{
+		R:      sdf.New(w, h),
+		G:      sdf.New(w, h),
+		B:      sdf.New(w, h),
+		Width:  w,
+		Height: h,
+	}
+}
+
+// At returns the red, green and blue field values at the given coordinate
+func (m *MSDF) At(x, y int) (float64, float64, float64) {
+	return m.R.At(x, y), m.G.At(x, y), m.B.At(x, y)
+}
+
+// Set writes the red, green and blue field values at the given coordinate
+func (m *MSDF) Set(x, y int, r, g, b float64) {
+	m.R.Set(x, y, r)
+	m.G.Set(x, y, g)
+	m.B.Set(x, y, b)
+}
+
+// edgeDistance is the signed distance to an edge along with a measure of how obliquely it was
+// approached, which breaks ties between edges meeting at a corner
+type edgeDistance struct {
+	dist float64
+	dot  float64
+}
+
+func (a edgeDistance) less(b edgeDistance) bool {
+	if da, db := math.Abs(a.dist), math.Abs(b.dist); math.Abs(da-db) > 1e-12 {
+		return da < db
+	}
+	return a.dot < b.dot
+}
+
+// signedEdgeDistance returns the signed distance from p to the edge, with the sign given by the
+// side of the edge p is on, along with the parameter of the nearest point on the edge
+func signedEdgeDistance(e vector.Segment, p vector.Vec) (edgeDistance, float64) {
+	t := e.Nearest(p)
+	q := e.Point(t)
+	qp := p.Sub(q)
+	dir := e.Direction(t).Normalize()
+
+	// the interior of an oriented shape is to the right of the edge, where the cross product is +ve
+	d := qp.Len()
+	if dir.Cross(qp) > 0 {
+		d = -d
+	}
+
+	return edgeDistance{d, math.Abs(dir.Dot(qp.Normalize()))}, t
+}
+
+// pseudoDistance extends the edge along its tangent beyond its end points, so that the distance
+// beyond an end point is measured perpendicular to the tangent rather than to the end point
+func pseudoDistance(e vector.Segment, p vector.Vec, d edgeDistance, t float64) float64 {
+	if t > 0 && t < 1 {
+		return d.dist
+	}
+
+	dir := e.Direction(t).Normalize()
+	qp := p.Sub(e.Point(t))
+	along := qp.Dot(dir)
+
+	if (t == 0 && along < 0) || (t == 1 && along > 0) {
+		pd := -dir.Cross(qp)
+		if math.Abs(pd) <= math.Abs(d.dist) {
+			return pd
+		}
+	}
+
+	return d.dist
+}
+
+// Generate computes an MSDF of the given size from a shape whose coordinates are in pixels.
+// The centre of the pixel at (x, y) is sampled at (x+0.5, y+0.5). Edges are coloured with
+// ColorEdges using an angle threshold of 3 radians.
+func Generate(shape *vector.Shape, w, h int) *MSDF {
+	oriented := &vector.Shape{Contours: append([]vector.Contour{}, shape.Contours...), FillRule: shape.FillRule}
+	oriented.Orient()
+	return GenerateFromEdges(shape, ColorEdges(oriented, 3), w, h)
+}
+
+// GenerateFromEdges computes an MSDF of the given size from the coloured edges of a shape,
+// which must be oriented as by vector.Shape.Orient. The shape itself is used only to correct
+// the sign of pixels where the channels disagree about whether they are inside the shape.
+func GenerateFromEdges(shape *vector.Shape, contours [][]Edge, w, h int) *MSDF {
+	m := New(w, h)
+	channels := [3]Color{Red, Green, Blue}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := vector.Vec{X: float64(x) + 0.5, Y: float64(y) + 0.5}
+
+			var values [3]float64
+			for ch, channel := range channels {
+				nearest := edgeDistance{math.Inf(1), 0}
+				var nearestEdge vector.Segment
+				var nearestT float64
+
+				for _, c := range contours {
+					for _, e := range c {
+						if e.Color&channel == 0 {
+							continue
+						}
+						if d, t := signedEdgeDistance(e.Segment, p); d.less(nearest) {
+							nearest, nearestEdge, nearestT = d, e.Segment, t
+						}
+					}
+				}
+
+				values[ch] = nearest.dist
+				if nearestEdge != nil {
+					values[ch] = pseudoDistance(nearestEdge, p, nearest, nearestT)
+				}
+			}
+
+			// pseudo-distances can disagree about the sign far from the edges,
+			// so flip the channels wherever the median contradicts the shape
+			if (median(values[0], values[1], values[2]) <= 0) != shape.Contains(p) {
+				values[0], values[1], values[2] = -values[0], -values[1], -values[2]
+			}
+
+			m.Set(x, y, values[0], values[1], values[2])
+		}
+	}
+
+	return m
+}
+
+func median(a, b, c float64) float64 {
+	return math.Max(math.Min(a, b), math.Min(math.Max(a, b), c))
+}
+
+// Median returns the single-channel SDF of the median of the three channels at each pixel
+func (m *MSDF) Median() *sdf.SDF {
+	ret := sdf.New(m.Width, m.Height)
+	for i := range ret.Field {
+		ret.Field[i] = median(m.R.Field[i], m.G.Field[i], m.B.Field[i])
+	}
+	return ret
+}
+
+// Draw returns an RGB image of the MSDF where each channel maps distances in range
+// [-spread, spread] to [255, 0], so the inside of the shape is where the median exceeds 50%.
+// This is the encoding conventionally expected by MSDF text shaders.
+func (m *MSDF) Draw(spread float64) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, m.Width, m.Height))
+
+	encode := func(d float64) uint8 {
+		v := 0.5 - d/(2*spread)
+		return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			r, g, b := m.At(x, y)
+			img.SetRGBA(x, y, color.RGBA{encode(r), encode(g), encode(b), 255})
+		}
+	}
+
+	return img
+}
+
+// Render returns an alpha mask of the shape at the given scale, by bilinearly interpolating the
+// three channels and taking their median, with a one output-pixel wide anti-aliased edge
+func (m *MSDF) Render(scale float64) *image.Alpha {
+	w, h := int(math.Ceil(float64(m.Width)*scale)), int(math.Ceil(float64(m.Height)*scale))
+	img := image.NewAlpha(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// map the output pixel centre back into field coordinates
+			fx := (float64(x) + 0.5) / scale
+			fy := (float64(y) + 0.5) / scale
+
+			d := median(m.R.Sample(fx, fy), m.G.Sample(fx, fy), m.B.Sample(fx, fy))
+
+			coverage := math.Max(0, math.Min(1, 0.5-d*scale))
+			img.SetAlpha(x, y, color.Alpha{uint8(math.Round(coverage * 255))})
+		}
+	}
+
+	return img
+}
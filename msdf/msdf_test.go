@@ -0,0 +1,116 @@
+package msdf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+func TestNew(t *testing.T) {
+	m := New(3, 5)
+
+	if m.Width != 3 || m.Height != 5 {
+		t.Errorf("Size should equal (3, 5), not (%v, %v)", m.Width, m.Height)
+	}
+
+	for _, ch := range []*sdf.SDF{m.R, m.G, m.B} {
+		if ch.Width != 3 || ch.Height != 5 {
+			t.Errorf("Channel size should equal (3, 5), not (%v, %v)", ch.Width, ch.Height)
+		}
+	}
+}
+
+func TestGenerateSignsMatchShape(t *testing.T) {
+	shapes := []*vector.Shape{
+		{Contours: []vector.Contour{square(4, 4, 12, 12)}},
+		// clockwise and anti-clockwise contours must both work
+		{Contours: []vector.Contour{square(4, 4, 12, 12).Reverse(), square(6, 6, 10, 10)}},
+		mustParse(t, "{ 2, 8; (2, 2; 14, 2); 14, 8; 8, 14; # }"),
+	}
+
+	for i, shape := range shapes {
+		m := Generate(shape, 16, 16)
+		med := m.Median()
+
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 16; x++ {
+				p := vector.Vec{X: float64(x) + 0.5, Y: float64(y) + 0.5}
+				if math.Abs(shape.SignedDistance(p)) > 1e-9 && (med.At(x, y) <= 0) != shape.Contains(p) {
+					t.Errorf("Shape %v: median at (%v, %v) of %v should agree with the shape about being inside", i, x, y, med.At(x, y))
+				}
+
+				// along the middle of the square's sides the median is the true distance
+				if i == 0 && x > 5 && x < 10 {
+					if d := shape.SignedDistance(p); math.Abs(med.At(x, y)-d) > 1e-9 {
+						t.Errorf("Shape %v: median at (%v, %v) should equal the true distance %v, not %v", i, x, y, d, med.At(x, y))
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRenderPreservesCorners(t *testing.T) {
+	shape := &vector.Shape{Contours: []vector.Contour{square(4, 4, 12, 12)}}
+	m := Generate(shape, 16, 16)
+
+	// a single-channel field of the true distances, for comparison
+	single := New(16, 16)
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			d := shape.SignedDistance(vector.Vec{X: float64(x) + 0.5, Y: float64(y) + 0.5})
+			single.Set(x, y, d, d, d)
+		}
+	}
+
+	// just inside the bottom-right corner at (12, 12), the single-channel field rounds the
+	// corner off whereas the MSDF keeps it sharp
+	const scale = 8
+	x, y := 94, 94
+
+	if a := m.Render(scale).AlphaAt(x, y).A; a < 128 {
+		t.Errorf("MSDF should render just inside the corner as covered, not %v", a)
+	}
+
+	if a := single.Render(scale).AlphaAt(x, y).A; a >= 128 {
+		t.Errorf("Single-channel field should render just inside the corner as uncovered, not %v", a)
+	}
+}
+
+func TestDraw(t *testing.T) {
+	m := New(3, 1)
+	m.Set(0, 0, 0, 0, 0)
+	m.Set(1, 0, -4, 2, 4)
+	m.Set(2, 0, -10, 10, 0)
+
+	img := m.Draw(4)
+
+	tests := []struct {
+		x       int
+		r, g, b uint8
+	}{
+		{0, 128, 128, 128},
+		{1, 255, 64, 0},
+		{2, 255, 0, 128},
+	}
+
+	for _, tt := range tests {
+		c := img.RGBAAt(tt.x, 0)
+		if c.R != tt.r || c.G != tt.g || c.B != tt.b || c.A != 255 {
+			t.Errorf("Pixel %v should be drawn as (%v, %v, %v, 255), not %v", tt.x, tt.r, tt.g, tt.b, c)
+		}
+	}
+}
+
+func TestMedian(t *testing.T) {
+	m := New(2, 1)
+	m.Set(0, 0, 3, -1, 2)
+	m.Set(1, 0, -5, -6, 7)
+
+	med := m.Median()
+	if med.At(0, 0) != 2 || med.At(1, 0) != -5 {
+		t.Errorf("Median should be [2 -5], not %v", med.Field)
+	}
+}
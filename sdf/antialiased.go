@@ -0,0 +1,220 @@
+package sdf
+
+import "math"
+
+// antiAliased implements the anti-aliased Euclidean distance transform of Stefan Gustavson
+// (edtaa3). The fractional coverage of edge pixels, together with the local gradient of the
+// coverage, is used to estimate where the edge passes through each pixel to sub-pixel accuracy.
+//
+// The returned distances are signed (-ve inside) and the nearest points are those of the pixels
+// containing the nearest edge. Pixels that cannot reach any edge are at an infinite distance.
+func antiAliased(s CoverageStencil, r *runner) ([]float64, []point, error) {
+	w, h := s.Size()
+
+	coverage := make([]float64, w*h)
+	err := r.each(h, func(y int) {
+		for x := 0; x < w; x++ {
+			coverage[y*w+x] = math.Max(0, math.Min(1, s.Coverage(x, y)))
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// measure the distance to the edge from the outside of the shape
+	outside, outsideNearest, err := edtaa(coverage, w, h, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// and then again from the inside of the shape by inverting the coverage
+	for i := range coverage {
+		coverage[i] = 1 - coverage[i]
+	}
+	inside, insideNearest, err := edtaa(coverage, w, h, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nearest := outsideNearest
+	err = r.each(h, func(y int) {
+		for i := y * w; i < (y+1)*w; i++ {
+			outside[i] = math.Max(0, outside[i]) - math.Max(0, inside[i])
+			if outside[i] <= 0 {
+				nearest[i] = insideNearest[i]
+			}
+			if math.IsInf(outside[i], 0) {
+				nearest[i] = noPoint
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return outside, nearest, nil
+}
+
+// edtaa computes the unsigned distance from every pixel to the edge of the area covered by
+// the coverage image, with pixels inside the area being at a distance of zero.
+func edtaa(coverage []float64, w, h int, r *runner) ([]float64, []point, error) {
+	gx, gy, err := coverageGradient(coverage, w, h, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dist := make([]float64, w*h)
+	nearest := make([]point, w*h)
+	for i, a := range coverage {
+		nearest[i] = point{i % w, i / w}
+		switch {
+		case a <= 0:
+			dist[i] = math.Inf(1) // not reached yet
+		case a < 1:
+			dist[i] = edgeDistance(gx[i], gy[i], a)
+		default:
+			dist[i] = 0 // fully inside
+		}
+	}
+
+	// aaDistance estimates the distance from pixel p to the edge passing through pixel q
+	aaDistance := func(p, q point) float64 {
+		a := coverage[q.y*w+q.x]
+		if a == 0 {
+			return math.Inf(1)
+		}
+
+		dx, dy := float64(p.x-q.x), float64(p.y-q.y)
+		di := math.Sqrt(dx*dx + dy*dy)
+		if di == 0 {
+			return edgeDistance(gx[q.y*w+q.x], gy[q.y*w+q.x], a)
+		}
+		return di + edgeDistance(dx, dy, a)
+	}
+
+	// compare adopts the nearest edge of the neighbour at offset (dx, dy) if it is closer
+	changed := false
+	compare := func(x, y, dx, dy int) {
+		i := y*w + x
+		if dist[i] <= 0 {
+			return
+		}
+
+		nx, ny := x+dx, y+dy
+		if nx < 0 || ny < 0 || nx >= w || ny >= h {
+			return
+		}
+
+		candidate := nearest[ny*w+nx]
+		d := aaDistance(point{x, y}, candidate)
+		if d < dist[i]-1e-3 {
+			dist[i] = d
+			nearest[i] = candidate
+			changed = true
+		}
+	}
+
+	// repeat the sequential sweeps of 8SSEDT until the distances settle
+	for first := true; first || changed; first = false {
+		if !first {
+			r.grow(2 * h)
+		}
+		changed = false
+
+		err := r.sequence(h, func(y int) {
+			for x := 0; x < w; x++ {
+				compare(x, y, -1, 0)
+				compare(x, y, -1, -1)
+				compare(x, y, 0, -1)
+				compare(x, y, 1, -1)
+			}
+			for x := w - 1; x >= 0; x-- {
+				compare(x, y, 1, 0)
+			}
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = r.sequence(h, func(i int) {
+			y := h - 1 - i
+			for x := w - 1; x >= 0; x-- {
+				compare(x, y, 1, 0)
+				compare(x, y, 1, 1)
+				compare(x, y, 0, 1)
+				compare(x, y, -1, 1)
+			}
+			for x := 0; x < w; x++ {
+				compare(x, y, -1, 0)
+			}
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return dist, nearest, nil
+}
+
+// coverageGradient estimates the normalised gradient of the coverage at each edge pixel
+// using a Sobel-like operator. Other pixels, and those on the image border, have no gradient.
+func coverageGradient(coverage []float64, w, h int, r *runner) ([]float64, []float64, error) {
+	gx := make([]float64, w*h)
+	gy := make([]float64, w*h)
+
+	err := r.each(h, func(y int) {
+		if y == 0 || y == h-1 {
+			return
+		}
+
+		for x := 1; x < w-1; x++ {
+			i := y*w + x
+			if coverage[i] <= 0 || coverage[i] >= 1 {
+				continue
+			}
+
+			gx[i] = -coverage[i-w-1] - math.Sqrt2*coverage[i-1] - coverage[i+w-1] +
+				coverage[i-w+1] + math.Sqrt2*coverage[i+1] + coverage[i+w+1]
+			gy[i] = -coverage[i-w-1] - math.Sqrt2*coverage[i-w] - coverage[i-w+1] +
+				coverage[i+w-1] + math.Sqrt2*coverage[i+w] + coverage[i+w+1]
+
+			if length := math.Hypot(gx[i], gy[i]); length > 0 {
+				gx[i] /= length
+				gy[i] /= length
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gx, gy, nil
+}
+
+// edgeDistance estimates the distance from the centre of a pixel with coverage a to the edge
+// passing through it, assuming the edge is straight and perpendicular to the direction (gx, gy).
+func edgeDistance(gx, gy, a float64) float64 {
+	if gx == 0 || gy == 0 {
+		// the edge is axis-aligned (or its direction is unknown) so the estimate is linear
+		return 0.5 - a
+	}
+
+	length := math.Hypot(gx, gy)
+	gx, gy = math.Abs(gx/length), math.Abs(gy/length)
+	if gx < gy {
+		gx, gy = gy, gx
+	}
+
+	a1 := 0.5 * gy / gx
+	switch {
+	case a < a1:
+		// the edge clips a triangular corner of the pixel
+		return 0.5*(gx+gy) - math.Sqrt(2*gx*gy*a)
+	case a < 1-a1:
+		// the edge crosses the pixel from side to side
+		return (0.5 - a) * gx
+	default:
+		// the edge leaves only a triangular corner of the pixel uncovered
+		return -0.5*(gx+gy) + math.Sqrt(2*gx*gy*(1-a))
+	}
+}
@@ -0,0 +1,144 @@
+package sdf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// circleCoverageStencil is a CoverageStencil of a circle where the coverage of
+// each pixel is found by supersampling
+type circleCoverageStencil struct {
+	w, h        int
+	cx, cy, r   float64
+	supersample int
+}
+
+func (s circleCoverageStencil) Size() (int, int)     { return s.w, s.h }
+func (s circleCoverageStencil) Within(x, y int) bool { return s.Coverage(x, y) >= 0.5 }
+
+func (s circleCoverageStencil) Coverage(x, y int) float64 {
+	n := s.supersample
+	covered := 0
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			// pixel centres are at integer coordinates so samples span +/- half a pixel
+			dx := float64(x) - 0.5 + (float64(i)+0.5)/float64(n) - s.cx
+			dy := float64(y) - 0.5 + (float64(j)+0.5)/float64(n) - s.cy
+			if dx*dx+dy*dy <= s.r*s.r {
+				covered++
+			}
+		}
+	}
+	return float64(covered) / float64(n*n)
+}
+
+func (s circleCoverageStencil) trueDistance(x, y int) float64 {
+	return math.Hypot(float64(x)-s.cx, float64(y)-s.cy) - s.r
+}
+
+func TestAntiAliasedRequiresCoverageStencil(t *testing.T) {
+	df, err := CalculateWithOptions(stubStencil{}, Options{Algorithm: AntiAliased})
+
+	if df != nil {
+		t.Errorf("AntiAliased should not return a result for a Stencil without coverage")
+	}
+
+	if err == nil {
+		t.Errorf("AntiAliased should return an error for a Stencil without coverage")
+	}
+}
+
+func TestAntiAliasedStraightEdge(t *testing.T) {
+	// a vertical edge at x=2.3 (in pixel-centre coordinates) covers 80% of column 2
+	edge := maskCoverage{w: 6, h: 4, coverage: func(x, y int) float64 {
+		return math.Max(0, math.Min(1, 2.8-float64(x)))
+	}}
+
+	df, err := CalculateWithOptions(edge, Options{Algorithm: AntiAliased})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 6; x++ {
+			expected := float64(x) - 2.3
+			if f := df.At(x, y); math.Abs(f-expected) > 1e-9 {
+				t.Errorf("Field value at (%v, %v) should equal %v, not %v", x, y, expected, f)
+			}
+		}
+	}
+}
+
+func TestAntiAliasedCircle(t *testing.T) {
+	s := circleCoverageStencil{w: 48, h: 40, cx: 23.3, cy: 19.6, r: 12.4, supersample: 16}
+
+	aa, err := CalculateWithOptions(s, Options{Algorithm: AntiAliased})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	binary := Calculate(s)
+
+	// compare the errors of pixels close to the edge, where sub-pixel accuracy matters most
+	aaMaxErr, binaryMaxErr := 0.0, 0.0
+	for y := 0; y < s.h; y++ {
+		for x := 0; x < s.w; x++ {
+			d := s.trueDistance(x, y)
+			if math.Abs(d) > 3 {
+				continue
+			}
+
+			aaMaxErr = math.Max(aaMaxErr, math.Abs(aa.At(x, y)-d))
+			binaryMaxErr = math.Max(binaryMaxErr, math.Abs(binary.At(x, y)-d))
+
+			if (aa.At(x, y) <= 0) != (d <= 0) && math.Abs(d) > 0.1 {
+				t.Errorf("Field value at (%v, %v) should have the same sign as %v, not %v", x, y, d, aa.At(x, y))
+			}
+		}
+	}
+
+	if aaMaxErr > 0.25 {
+		t.Errorf("Anti-aliased field should be within 0.25 of the true distance near the edge, not %v", aaMaxErr)
+	}
+
+	if aaMaxErr >= binaryMaxErr {
+		t.Errorf("Anti-aliased field error of %v should be lower than the binary field error of %v", aaMaxErr, binaryMaxErr)
+	}
+}
+
+// maskCoverage is a CoverageStencil defined by a coverage function
+type maskCoverage struct {
+	w, h     int
+	coverage func(x, y int) float64
+}
+
+func (s maskCoverage) Size() (int, int)          { return s.w, s.h }
+func (s maskCoverage) Within(x, y int) bool      { return s.coverage(x, y) >= 0.5 }
+func (s maskCoverage) Coverage(x, y int) float64 { return s.coverage(x, y) }
+
+func TestAntiAliasedMatchesSerialAndReportsProgress(t *testing.T) {
+	s := circleCoverageStencil{w: 37, h: 29, cx: 15.2, cy: 13.9, r: 9.1, supersample: 4}
+
+	serial, err := CalculateWithOptions(s, Options{Algorithm: AntiAliased, Workers: 1})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	lastDone, lastTotal := 0, 0
+	parallel, err := CalculateWithOptions(s, Options{
+		Algorithm: AntiAliased,
+		Workers:   4,
+		Progress:  func(done, total int) { lastDone, lastTotal = done, total },
+	})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	if !reflect.DeepEqual(serial, parallel) {
+		t.Errorf("Parallel anti-aliased field should be identical to the serial field")
+	}
+
+	if lastDone == 0 || lastDone != lastTotal {
+		t.Errorf("Progress should finish with done equal to the total, not %v of %v", lastDone, lastTotal)
+	}
+}
@@ -0,0 +1,228 @@
+package sdf
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MarshalOptions controls how MarshalBinaryWithOptions encodes a field
+type MarshalOptions struct {
+	// Float32 stores distances as 32-bit floats rather than 64-bit, halving their size at the
+	// cost of precision
+	Float32 bool
+	// Compress deflates everything after the header
+	Compress bool
+}
+
+// The binary encoding is a 16 byte header followed by the distances in row-major order and, for
+// a DisplacementField, the X,Y coordinate of the nearest boundary point of every pixel as pairs
+// of int32s. Numbers are little-endian.
+//
+// The header is the magic "SDFB", a version byte, a kind byte (0 for SDF and 1 for
+// DisplacementField), an element type byte (4 for float32 and 8 for float64 distances), a flags
+// byte (bit 0 set if the rest is deflated) and then the width and height as uint32s.
+const (
+	binaryMagic   = "SDFB"
+	binaryVersion = 1
+	binaryHeader  = 16
+
+	kindSDF               = 0
+	kindDisplacementField = 1
+
+	flagCompressed = 1
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler, storing distances as uncompressed 64-bit
+// floats so that the field is restored exactly
+func (sdf *SDF) MarshalBinary() ([]byte, error) {
+	return sdf.MarshalBinaryWithOptions(MarshalOptions{})
+}
+
+// MarshalBinaryWithOptions encodes the field like MarshalBinary, with a choice of precision and
+// compression. UnmarshalBinary reads every choice.
+func (sdf *SDF) MarshalBinaryWithOptions(o MarshalOptions) ([]byte, error) {
+	return marshalBinary(sdf, nil, o)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The data may also hold a
+// DisplacementField, in which case only its distances are read.
+func (sdf *SDF) UnmarshalBinary(data []byte) error {
+	f, _, err := unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	*sdf = *f
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, storing distances as uncompressed 64-bit
+// floats along with the nearest boundary point of every pixel
+func (df *DisplacementField) MarshalBinary() ([]byte, error) {
+	return df.MarshalBinaryWithOptions(MarshalOptions{})
+}
+
+// MarshalBinaryWithOptions encodes the field like MarshalBinary, with a choice of precision and
+// compression. UnmarshalBinary reads every choice.
+func (df *DisplacementField) MarshalBinaryWithOptions(o MarshalOptions) ([]byte, error) {
+	if df.SDF == nil || df.boundaryPts == nil {
+		return nil, errors.New("displacement field has no nearest boundary points")
+	}
+	return marshalBinary(df.SDF, df.boundaryPts, o)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The data must hold a DisplacementField
+// rather than only an SDF.
+func (df *DisplacementField) UnmarshalBinary(data []byte) error {
+	f, pts, err := unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	if pts == nil {
+		return errors.New("binary data holds an SDF rather than a DisplacementField")
+	}
+	df.SDF, df.boundaryPts = f, pts
+	return nil
+}
+
+func marshalBinary(f *SDF, pts []point, o MarshalOptions) ([]byte, error) {
+	if f.Width < 0 || f.Height < 0 || uint64(f.Width) > math.MaxUint32 || uint64(f.Height) > math.MaxUint32 {
+		return nil, errors.New("field dimensions are out of range")
+	}
+	if uint64(f.Width)*uint64(f.Height) > MaxPixels {
+		return nil, errors.New("field is too large")
+	}
+	if len(f.Field) != f.Width*f.Height {
+		return nil, fmt.Errorf("field holds %v distances rather than %vx%v", len(f.Field), f.Width, f.Height)
+	}
+	if pts != nil && len(pts) != f.Width*f.Height {
+		return nil, fmt.Errorf("field holds %v nearest boundary points rather than %vx%v", len(pts), f.Width, f.Height)
+	}
+
+	header := make([]byte, binaryHeader)
+	copy(header, binaryMagic)
+	header[4] = binaryVersion
+	header[5] = kindSDF
+	if pts != nil {
+		header[5] = kindDisplacementField
+	}
+	header[6] = 8
+	if o.Float32 {
+		header[6] = 4
+	}
+	if o.Compress {
+		header[7] = flagCompressed
+	}
+	binary.LittleEndian.PutUint32(header[8:], uint32(f.Width))
+	binary.LittleEndian.PutUint32(header[12:], uint32(f.Height))
+
+	size := int(header[6])
+	payload := make([]byte, len(f.Field)*size+len(pts)*8)
+	for i, v := range f.Field {
+		if o.Float32 {
+			binary.LittleEndian.PutUint32(payload[4*i:], math.Float32bits(float32(v)))
+		} else {
+			binary.LittleEndian.PutUint64(payload[8*i:], math.Float64bits(v))
+		}
+	}
+	nearest := payload[len(f.Field)*size:]
+	for i, pt := range pts {
+		binary.LittleEndian.PutUint32(nearest[8*i:], uint32(int32(pt.x)))
+		binary.LittleEndian.PutUint32(nearest[8*i+4:], uint32(int32(pt.y)))
+	}
+
+	if !o.Compress {
+		return append(header, payload...), nil
+	}
+
+	buf := bytes.NewBuffer(header)
+	fw, err := flate.NewWriter(buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := fw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalBinary decodes a field, along with its nearest boundary points if the data holds a
+// DisplacementField
+func unmarshalBinary(data []byte) (*SDF, []point, error) {
+	if len(data) < binaryHeader || string(data[:4]) != binaryMagic {
+		return nil, nil, errors.New("not a binary encoded field")
+	}
+	if data[4] != binaryVersion {
+		return nil, nil, fmt.Errorf("binary encoding version %v is not supported", data[4])
+	}
+
+	kind, size, flags := data[5], int(data[6]), data[7]
+	if kind != kindSDF && kind != kindDisplacementField {
+		return nil, nil, fmt.Errorf("unknown binary field kind %v", kind)
+	}
+	if size != 4 && size != 8 {
+		return nil, nil, fmt.Errorf("unknown binary element size %v", size)
+	}
+	if flags&^flagCompressed != 0 {
+		return nil, nil, fmt.Errorf("unknown binary flags %#x", flags)
+	}
+
+	w := binary.LittleEndian.Uint32(data[8:])
+	h := binary.LittleEndian.Uint32(data[12:])
+	if uint64(w)*uint64(h) > MaxPixels {
+		return nil, nil, errors.New("field is too large")
+	}
+	n := int(w) * int(h)
+
+	expected := n * size
+	if kind == kindDisplacementField {
+		expected += n * 8
+	}
+
+	payload := data[binaryHeader:]
+	if flags&flagCompressed != 0 {
+		// reading at most one byte too many detects corrupt lengths without trusting the header
+		var buf bytes.Buffer
+		fr := flate.NewReader(bytes.NewReader(payload))
+		if _, err := buf.ReadFrom(io.LimitReader(fr, int64(expected)+1)); err != nil {
+			return nil, nil, err
+		}
+		payload = buf.Bytes()
+	}
+	if len(payload) != expected {
+		return nil, nil, fmt.Errorf("binary field should hold %v bytes of data, not %v", expected, len(payload))
+	}
+
+	f := New(int(w), int(h))
+	for i := range f.Field {
+		if size == 4 {
+			f.Field[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(payload[4*i:])))
+		} else {
+			f.Field[i] = math.Float64frombits(binary.LittleEndian.Uint64(payload[8*i:]))
+		}
+	}
+	if kind == kindSDF {
+		return f, nil, nil
+	}
+
+	pts := make([]point, n)
+	payload = payload[n*size:]
+	for i := range pts {
+		pt := point{
+			int(int32(binary.LittleEndian.Uint32(payload[8*i:]))),
+			int(int32(binary.LittleEndian.Uint32(payload[8*i+4:]))),
+		}
+		if pt != noPoint && (pt.x < 0 || pt.y < 0 || pt.x >= f.Width || pt.y >= f.Height) {
+			return nil, nil, fmt.Errorf("nearest boundary point (%v, %v) lies outside the field", pt.x, pt.y)
+		}
+		pts[i] = pt
+	}
+	return f, pts, nil
+}
@@ -0,0 +1,177 @@
+package sdf
+
+import (
+	"bytes"
+	"encoding"
+	"math"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*SDF)(nil)
+	_ encoding.BinaryUnmarshaler = (*SDF)(nil)
+	_ encoding.BinaryMarshaler   = (*DisplacementField)(nil)
+	_ encoding.BinaryUnmarshaler = (*DisplacementField)(nil)
+)
+
+func TestSDFBinaryRoundTrip(t *testing.T) {
+	f := newSDF(3, 2, -1.5, 0, math.Pi, 4, math.Inf(1), math.Inf(-1))
+
+	for _, o := range []MarshalOptions{{}, {Compress: true}, {Float32: true}, {Float32: true, Compress: true}} {
+		data, err := f.MarshalBinaryWithOptions(o)
+		if err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+
+		var read SDF
+		if err := read.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		if read.Width != 3 || read.Height != 2 {
+			t.Fatalf("Size should be 3x2, not %vx%v", read.Width, read.Height)
+		}
+
+		for i, v := range f.Field {
+			expected := v
+			if o.Float32 {
+				expected = float64(float32(v))
+			}
+			if read.Field[i] != expected {
+				t.Errorf("Value %v with options %+v should be %v, not %v", i, o, expected, read.Field[i])
+			}
+		}
+	}
+}
+
+func TestSDFMarshalBinaryHeader(t *testing.T) {
+	data, err := newSDF(3, 2, 0, 0, 0, 0, 0, 0).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	header := []byte{'S', 'D', 'F', 'B', 1, 0, 8, 0, 3, 0, 0, 0, 2, 0, 0, 0}
+	if !bytes.Equal(data[:16], header) || len(data) != 16+8*6 {
+		t.Errorf("Data should be the header %v followed by 6 float64s, not %v", header, data)
+	}
+}
+
+func TestSDFBinaryCompression(t *testing.T) {
+	f := New(64, 64)
+	plain, _ := f.MarshalBinary()
+	compressed, _ := f.MarshalBinaryWithOptions(MarshalOptions{Compress: true})
+	if len(compressed) >= len(plain) {
+		t.Errorf("Compressed data should be smaller than %v bytes, not %v", len(plain), len(compressed))
+	}
+}
+
+func TestDisplacementFieldBinaryRoundTrip(t *testing.T) {
+	df := Calculate(circleStencil(9, 7, 4, 3, 2.5))
+
+	for _, o := range []MarshalOptions{{}, {Compress: true}} {
+		data, err := df.MarshalBinaryWithOptions(o)
+		if err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+
+		var read DisplacementField
+		if err := read.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+
+		for y := 0; y < df.Height; y++ {
+			for x := 0; x < df.Width; x++ {
+				if read.At(x, y) != df.At(x, y) {
+					t.Errorf("Distance at (%v, %v) should be %v, not %v", x, y, df.At(x, y), read.At(x, y))
+				}
+				ex, ey := df.NearestBoundaryAt(x, y)
+				ax, ay := read.NearestBoundaryAt(x, y)
+				if ax != ex || ay != ey {
+					t.Errorf("Nearest boundary of (%v, %v) should be (%v, %v), not (%v, %v)", x, y, ex, ey, ax, ay)
+				}
+			}
+		}
+
+		// the distances alone can be read back as an SDF
+		var f SDF
+		if err := f.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		if f.Width != df.Width || f.Height != df.Height {
+			t.Errorf("Size should be %vx%v, not %vx%v", df.Width, df.Height, f.Width, f.Height)
+		}
+	}
+}
+
+func TestDisplacementFieldBinaryNoBoundary(t *testing.T) {
+	df := NewDisplacementField(2, 2)
+	data, err := df.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	var read DisplacementField
+	if err := read.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if _, _, ok := read.LookupNearestBoundary(1, 1); ok {
+		t.Errorf("Field without a boundary should have no nearest boundary point")
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	sdfData, _ := newSDF(1, 1, 0).MarshalBinary()
+	dfData, _ := NewDisplacementField(1, 1).MarshalBinary()
+	compressed, _ := newSDF(1, 1, 0).MarshalBinaryWithOptions(MarshalOptions{Compress: true})
+
+	corrupt := func(data []byte, i int, b byte) []byte {
+		data = append([]byte(nil), data...)
+		data[i] = b
+		return data
+	}
+
+	for _, data := range [][]byte{
+		{},
+		corrupt(sdfData, 0, 'X'),
+		corrupt(sdfData, 4, 2),
+		corrupt(sdfData, 5, 2),
+		corrupt(sdfData, 6, 2),
+		corrupt(sdfData, 7, 2),
+		corrupt(sdfData, 8, 2),
+		corrupt(sdfData, 11, 0xff),
+		sdfData[:len(sdfData)-1],
+		compressed[:len(compressed)-2],
+		corrupt(dfData, 24, 5),
+	} {
+		var f DisplacementField
+		if err := f.UnmarshalBinary(data); err == nil {
+			t.Errorf("Unmarshalling %v should return an error", data)
+		}
+	}
+
+	var df DisplacementField
+	if err := df.UnmarshalBinary(sdfData); err == nil {
+		t.Errorf("Unmarshalling an SDF as a DisplacementField should return an error")
+	}
+}
+
+func TestMarshalBinaryErrors(t *testing.T) {
+	short := newSDF(2, 2, 0)
+	short.Field = short.Field[:3]
+
+	mismatched := NewDisplacementField(2, 2)
+	mismatched.boundaryPts = mismatched.boundaryPts[:3]
+
+	for _, m := range []interface {
+		MarshalBinary() ([]byte, error)
+	}{
+		short,
+		&SDF{Width: -1, Height: 1},
+		&SDF{Width: MaxPixels, Height: 2},
+		mismatched,
+		&DisplacementField{SDF: newSDF(1, 1, 0)},
+	} {
+		if _, err := m.MarshalBinary(); err == nil {
+			t.Errorf("Marshalling %v should return an error", m)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package sdf
+
+// bruteForce measures every pixel against every site. It is O(pixels * sites) so is
+// only practical for small inputs, but is trivially correct which makes it a useful reference.
+func bruteForce(w, h int, sites []bool, r *runner) ([]point, error) {
+	pts := []point{}
+	for i, isSite := range sites {
+		if isSite {
+			pts = append(pts, point{i % w, i / w})
+		}
+	}
+
+	nearest := make([]point, w*h)
+	err := r.each(h, func(y int) {
+		for x := 0; x < w; x++ {
+			pt, _ := point{x, y}.nearest(pts)
+			if pt == nil {
+				nearest[y*w+x] = noPoint
+			} else {
+				nearest[y*w+x] = *pt
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nearest, nil
+}
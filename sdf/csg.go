@@ -0,0 +1,90 @@
+package sdf
+
+import "math"
+
+// Union returns the field of the region inside either a or b
+func Union(a *SDF, b *SDF) (*SDF, error) {
+	return combine(a, b, math.Min)
+}
+
+// Intersection returns the field of the region inside both a and b
+func Intersection(a *SDF, b *SDF) (*SDF, error) {
+	return combine(a, b, math.Max)
+}
+
+// Subtraction returns the field of the region inside a but not inside b
+func Subtraction(a *SDF, b *SDF) (*SDF, error) {
+	return combine(a, b, SubtractDistance)
+}
+
+// Xor returns the field of the region inside exactly one of a and b
+func Xor(a *SDF, b *SDF) (*SDF, error) {
+	return combine(a, b, XorDistance)
+}
+
+// SmoothUnion returns the union of a and b with the seams where they meet blended over a
+// distance of about k, using the given smooth minimum
+func SmoothUnion(a *SDF, b *SDF, k float64, smin SmoothMin) (*SDF, error) {
+	return combine(a, b, func(da, db float64) float64 {
+		return smin(da, db, k)
+	})
+}
+
+// SmoothIntersection returns the intersection of a and b with the creases where they meet
+// blended over a distance of about k, using the given smooth minimum
+func SmoothIntersection(a *SDF, b *SDF, k float64, smin SmoothMin) (*SDF, error) {
+	return combine(a, b, func(da, db float64) float64 {
+		return -smin(-da, -db, k)
+	})
+}
+
+// SmoothSubtraction returns a with b cut out of it, with the creases where they meet blended
+// over a distance of about k, using the given smooth minimum
+func SmoothSubtraction(a *SDF, b *SDF, k float64, smin SmoothMin) (*SDF, error) {
+	return combine(a, b, func(da, db float64) float64 {
+		return -smin(-da, db, k)
+	})
+}
+
+// SubtractDistance combines the signed distances to two shapes into the distance to the region
+// inside the first but not the second
+func SubtractDistance(da, db float64) float64 {
+	return math.Max(da, -db)
+}
+
+// XorDistance combines the signed distances to two shapes into the distance to the region
+// inside exactly one of them
+func XorDistance(da, db float64) float64 {
+	return math.Max(math.Min(da, db), -math.Max(da, db))
+}
+
+// SmoothMin is a smooth approximation of math.Min, where k controls the distance over which
+// the two values are blended. A k of zero gives the ordinary minimum.
+//
+// Blended fields remain a bound on the distance to the edge, rather than an exact distance,
+// within about k of a seam.
+type SmoothMin func(a, b, k float64) float64
+
+// PolySmoothMin is a quadratic polynomial smooth minimum which only differs from math.Min
+// where a and b are within k of each other
+func PolySmoothMin(a, b, k float64) float64 {
+	d := math.Abs(a - b)
+	if k <= 0 || !(d < k) {
+		return math.Min(a, b)
+	}
+	h := (k - d) / k
+	return math.Min(a, b) - h*h*k/4
+}
+
+// ExpSmoothMin is an exponential smooth minimum which blends a and b everywhere, giving smoother
+// but wider seams than PolySmoothMin
+func ExpSmoothMin(a, b, k float64) float64 {
+	m := math.Min(a, b)
+	d := math.Abs(a - b)
+	if k <= 0 || math.IsNaN(d) {
+		return m
+	}
+
+	// the same as -k*log2(2^(-a/k) + 2^(-b/k)) but without overflowing for large distances
+	return m - k*math.Log2(1+math.Exp2(-d/k))
+}
@@ -0,0 +1,121 @@
+package sdf
+
+import (
+	"math"
+	"testing"
+)
+
+func newSDF(w, h int, values ...float64) *SDF {
+	s := New(w, h)
+	copy(s.Field, values)
+	return s
+}
+
+func TestCSG(t *testing.T) {
+	a := newSDF(4, 1, -2, -1, 1, 3)
+	b := newSDF(4, 1, -1, 2, -3, 4)
+
+	tests := []struct {
+		name     string
+		op       func(a, b *SDF) (*SDF, error)
+		expected []float64
+	}{
+		{"Union", Union, []float64{-2, -1, -3, 3}},
+		{"Intersection", Intersection, []float64{-1, 2, 1, 4}},
+		{"Subtraction", Subtraction, []float64{1, -1, 3, 3}},
+		{"Xor", Xor, []float64{1, -1, -1, 3}},
+	}
+
+	for _, tt := range tests {
+		res, err := tt.op(a, b)
+		if err != nil {
+			t.Fatalf("%v: error should have been nil, not %v", tt.name, err)
+		}
+		for i, expected := range tt.expected {
+			if res.Field[i] != expected {
+				t.Errorf("%v: value %v should be %v, not %v", tt.name, i, expected, res.Field[i])
+			}
+		}
+	}
+}
+
+func TestCSGWithMismatchedSDFs(t *testing.T) {
+	a := New(4, 4)
+	ops := map[string]func(a, b *SDF) (*SDF, error){
+		"Union":        Union,
+		"Intersection": Intersection,
+		"Subtraction":  Subtraction,
+		"Xor":          Xor,
+		"SmoothUnion": func(a, b *SDF) (*SDF, error) {
+			return SmoothUnion(a, b, 1, PolySmoothMin)
+		},
+		"SmoothIntersection": func(a, b *SDF) (*SDF, error) {
+			return SmoothIntersection(a, b, 1, PolySmoothMin)
+		},
+		"SmoothSubtraction": func(a, b *SDF) (*SDF, error) {
+			return SmoothSubtraction(a, b, 1, PolySmoothMin)
+		},
+	}
+
+	for name, op := range ops {
+		for _, b := range []*SDF{New(5, 4), New(4, 5)} {
+			res, err := op(a, b)
+			if res != nil || err == nil {
+				t.Errorf("%v should return an error and no result when SDFs are mismatched sizes", name)
+			}
+		}
+	}
+}
+
+func TestSmoothMin(t *testing.T) {
+	for name, smin := range map[string]SmoothMin{"PolySmoothMin": PolySmoothMin, "ExpSmoothMin": ExpSmoothMin} {
+		// smooth minimums never exceed the true minimum, and equal it with no smoothing
+		for _, ab := range [][2]float64{{0, 0}, {1, 2}, {-3, 2}, {5, 5.5}} {
+			a, b := ab[0], ab[1]
+			if m := smin(a, b, 1); m > math.Min(a, b) {
+				t.Errorf("%v(%v, %v) should not exceed the minimum, not %v", name, a, b, m)
+			}
+			if m := smin(a, b, 0); m != math.Min(a, b) {
+				t.Errorf("%v(%v, %v) with k=0 should be the minimum, not %v", name, a, b, m)
+			}
+			if smin(a, b, 1) != smin(b, a, 1) {
+				t.Errorf("%v(%v, %v) should be symmetric", name, a, b)
+			}
+		}
+
+		if m := smin(math.Inf(1), math.Inf(1), 1); !math.IsInf(m, 1) {
+			t.Errorf("%v of infinite distances should be infinite, not %v", name, m)
+		}
+		if m := smin(1e6, -1e6, 1); m != -1e6 {
+			t.Errorf("%v of distant values should be the minimum, not %v", name, m)
+		}
+	}
+
+	if m := PolySmoothMin(0, 0, 4); m != -1 {
+		t.Errorf("PolySmoothMin of equal values should be lowered by k/4, not %v", m)
+	}
+	if m := PolySmoothMin(0, 4, 4); m != 0 {
+		t.Errorf("PolySmoothMin of values k apart should be the minimum, not %v", m)
+	}
+	if m := ExpSmoothMin(0, 0, 4); m != -4 {
+		t.Errorf("ExpSmoothMin of equal values should be lowered by k, not %v", m)
+	}
+}
+
+func TestSmoothCSG(t *testing.T) {
+	a := newSDF(3, 1, -1, 0, 4)
+	b := newSDF(3, 1, 4, 0, -1)
+
+	union, _ := SmoothUnion(a, b, 2, PolySmoothMin)
+	intersection, _ := SmoothIntersection(a, b, 2, PolySmoothMin)
+	subtraction, _ := SmoothSubtraction(a, b, 2, PolySmoothMin)
+
+	// where the fields agree the seam is blended by k/4
+	if union.Field[1] != -0.5 || intersection.Field[1] != 0.5 {
+		t.Errorf("Smooth union and intersection should be -0.5 and 0.5 on the seam, not %v and %v", union.Field[1], intersection.Field[1])
+	}
+	// away from the seam the hard result is kept
+	if union.Field[0] != -1 || intersection.Field[2] != 4 || subtraction.Field[2] != 4 {
+		t.Errorf("Smooth operations should match the hard ones away from the seam")
+	}
+}
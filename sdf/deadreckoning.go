@@ -0,0 +1,73 @@
+package sdf
+
+// deadReckoning implements the 8-point Sequential Signed Euclidean Distance Transform (8SSEDT)
+// with dead-reckoning: rather than propagating accumulated distances, each pixel propagates the
+// location of its nearest site so that distances are always measured exactly to a real site.
+//
+// Two raster passes are made, each looking at the neighbours already visited. This is fast and
+// simple but can occasionally pick a site that is slightly further than the true nearest one.
+// Each row depends on the rows before it so the passes always run sequentially.
+func deadReckoning(w, h int, sites []bool, r *runner) ([]point, error) {
+	nearest := make([]point, w*h)
+	for i, isSite := range sites {
+		if isSite {
+			nearest[i] = point{i % w, i / w}
+		} else {
+			nearest[i] = noPoint
+		}
+	}
+
+	// compare adopts the nearest site of the neighbour at offset (dx, dy) if it is closer
+	compare := func(x, y, dx, dy int) {
+		nx, ny := x+dx, y+dy
+		if nx < 0 || ny < 0 || nx >= w || ny >= h {
+			return
+		}
+
+		candidate := nearest[ny*w+nx]
+		if candidate == noPoint {
+			return
+		}
+
+		p := point{x, y}
+		current := nearest[y*w+x]
+		if current == noPoint || p.dstSq(candidate) < p.dstSq(current) {
+			nearest[y*w+x] = candidate
+		}
+	}
+
+	// first pass sweeps downwards, pulling sites from above
+	err := r.sequence(h, func(y int) {
+		for x := 0; x < w; x++ {
+			compare(x, y, -1, 0)
+			compare(x, y, 0, -1)
+			compare(x, y, -1, -1)
+			compare(x, y, 1, -1)
+		}
+		for x := w - 1; x >= 0; x-- {
+			compare(x, y, 1, 0)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// second pass sweeps upwards, pulling sites from below
+	err = r.sequence(h, func(i int) {
+		y := h - 1 - i
+		for x := w - 1; x >= 0; x-- {
+			compare(x, y, 1, 0)
+			compare(x, y, 0, 1)
+			compare(x, y, -1, 1)
+			compare(x, y, 1, 1)
+		}
+		for x := 0; x < w; x++ {
+			compare(x, y, -1, 0)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nearest, nil
+}
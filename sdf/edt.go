@@ -0,0 +1,127 @@
+package sdf
+
+import "math"
+
+// edt computes an exact Euclidean Distance Transform over a w*h grid, returning for every
+// pixel the nearest site as flagged in the sites mask.
+//
+// This is the separable algorithm of Felzenszwalb & Huttenlocher: a 1D transform down each
+// column finds the nearest site within that column, then a 1D transform along each row takes
+// the lower envelope of the parabolas rooted at those column results. Both passes are linear,
+// so the whole transform is O(w*h) regardless of how many sites there are.
+//
+// Every column of the first pass and every row of the second pass is independent, so each
+// pass is spread across the runner's workers.
+func edt(w, h int, sites []bool, r *runner) ([]point, error) {
+	nearestRow := make([]int, w*h)
+	err := r.each(w, func(x int) {
+		edtColumn(x, w, h, sites, nearestRow)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nearest := make([]point, w*h)
+	err = r.each(h, func(y int) {
+		edtRow(y, w, nearestRow, nearest, newEnvelope(w))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nearest, nil
+}
+
+// edtColumn writes the row of the nearest site within column x into nearestRow,
+// or -1 if the column has no sites at all.
+func edtColumn(x, w, h int, sites []bool, nearestRow []int) {
+	// sweep downwards to find the nearest site above (or at) each pixel
+	last := -1
+	for y := 0; y < h; y++ {
+		if sites[y*w+x] {
+			last = y
+		}
+		nearestRow[y*w+x] = last
+	}
+
+	// sweep upwards and keep whichever of the sites above or below is closer
+	next := -1
+	for y := h - 1; y >= 0; y-- {
+		if sites[y*w+x] {
+			next = y
+		}
+		above := nearestRow[y*w+x]
+		if next != -1 && (above == -1 || next-y < y-above) {
+			nearestRow[y*w+x] = next
+		}
+	}
+}
+
+// envelope is the scratch space for computing the lower envelope of parabolas along a row
+type envelope struct {
+	v []int     // x-coordinates of the parabolas forming the lower envelope
+	z []float64 // boundaries between the parabolas of the lower envelope
+}
+
+func newEnvelope(n int) *envelope {
+	return &envelope{
+		v: make([]int, n),
+		z: make([]float64, n+1),
+	}
+}
+
+// edtRow resolves the nearest site for every pixel of row y given the per-column results
+func edtRow(y, w int, nearestRow []int, nearest []point, env *envelope) {
+	row := nearestRow[y*w : (y+1)*w]
+
+	// f is the squared vertical distance to the nearest site in column q
+	f := func(q int) float64 {
+		dy := float64(row[q] - y)
+		return dy * dy
+	}
+
+	// build the lower envelope from only those columns that contain a site
+	k := -1
+	for q := 0; q < w; q++ {
+		if row[q] == -1 {
+			continue
+		}
+
+		var s float64
+		for k >= 0 {
+			p := env.v[k]
+			s = ((f(q) + float64(q*q)) - (f(p) + float64(p*p))) / float64(2*q-2*p)
+			if s > env.z[k] {
+				break
+			}
+			k--
+		}
+
+		k++
+		env.v[k] = q
+		if k == 0 {
+			env.z[k] = math.Inf(-1)
+		} else {
+			env.z[k] = s
+		}
+		env.z[k+1] = math.Inf(1)
+	}
+
+	out := nearest[y*w : (y+1)*w]
+	if k == -1 {
+		for x := range out {
+			out[x] = noPoint
+		}
+		return
+	}
+
+	// walk the envelope to find which parabola lies beneath each pixel
+	k = 0
+	for x := 0; x < w; x++ {
+		for env.z[k+1] < float64(x) {
+			k++
+		}
+		q := env.v[k]
+		out[x] = point{q, row[q]}
+	}
+}
@@ -0,0 +1,97 @@
+package sdf
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// maskStencil is a Stencil backed by a simple boolean mask
+type maskStencil struct {
+	w, h int
+	mask []bool
+}
+
+func (s maskStencil) Size() (int, int)     { return s.w, s.h }
+func (s maskStencil) Within(x, y int) bool { return s.mask[y*s.w+x] }
+
+func randomStencil(rng *rand.Rand, w, h int, density float64) maskStencil {
+	s := maskStencil{w, h, make([]bool, w*h)}
+	for i := range s.mask {
+		s.mask[i] = rng.Float64() < density
+	}
+	// guarantee the stencil is never empty so there is always a boundary to measure against
+	s.mask[rng.Intn(w*h)] = true
+	return s
+}
+
+func circleStencil(w, h int, cx, cy, r float64) maskStencil {
+	s := maskStencil{w, h, make([]bool, w*h)}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := float64(x)-cx, float64(y)-cy
+			s.mask[y*w+x] = dx*dx+dy*dy <= r*r
+		}
+	}
+	return s
+}
+
+// boundaryMask serially finds the boundaries of a stencil
+func boundaryMask(s Stencil) []bool {
+	boundary, _ := findBoundaries(s, newRunner(context.Background(), 1, nil, 0))
+	return boundary
+}
+
+func assertMatchesBruteForce(t *testing.T, name string, s Stencil) {
+	t.Helper()
+
+	w, h := s.Size()
+	boundary := boundaryMask(s)
+	expected, err := CalculateWithOptions(s, Options{Algorithm: BruteForce})
+	if err != nil {
+		t.Fatalf("%v: error should have been nil, not %v", name, err)
+	}
+	actual := Calculate(s)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			exp, act := expected.At(x, y), actual.At(x, y)
+			if exp != act {
+				t.Fatalf("%v: field value at (%v, %v) should equal %v, not %v", name, x, y, exp, act)
+			}
+
+			// ties between equidistant boundary points may resolve differently,
+			// so the nearest point need only be an equally near boundary point
+			nx, ny := actual.NearestBoundaryAt(x, y)
+			if !boundary[ny*w+nx] {
+				t.Fatalf("%v: nearest boundary at (%v, %v) is (%v, %v) which is not a boundary point", name, x, y, nx, ny)
+			}
+			if dst := math.Sqrt(point{x, y}.dstSq(point{nx, ny})); dst != math.Abs(exp) {
+				t.Fatalf("%v: nearest boundary at (%v, %v) should be %v away, not %v", name, x, y, math.Abs(exp), dst)
+			}
+		}
+	}
+}
+
+func TestCalculateMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	assertMatchesBruteForce(t, "stub", stubStencil{})
+	assertMatchesBruteForce(t, "single pixel", maskStencil{1, 1, []bool{true}})
+	assertMatchesBruteForce(t, "circle", circleStencil(40, 30, 12.5, 17, 9))
+	assertMatchesBruteForce(t, "off-centre circle", circleStencil(33, 47, -3, 50, 20))
+
+	for i := 0; i < 20; i++ {
+		w, h := 1+rng.Intn(40), 1+rng.Intn(40)
+		assertMatchesBruteForce(t, "random sparse", randomStencil(rng, w, h, 0.02))
+		assertMatchesBruteForce(t, "random dense", randomStencil(rng, w, h, 0.6))
+	}
+}
+
+func BenchmarkCalculate(b *testing.B) {
+	s := circleStencil(512, 512, 256, 256, 200)
+	for i := 0; i < b.N; i++ {
+		Calculate(s)
+	}
+}
@@ -0,0 +1,112 @@
+package sdf
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Encoding describes how distances are stored as gray levels in an image.
+// Distances map linearly to gray levels, with a distance of 2*Spread covering the full range of
+// levels. Distances beyond the range saturate at black or white.
+type Encoding struct {
+	// Spread is half of the range of distances, in pixels, that are encoded
+	Spread float64
+	// ZeroLevel is the gray level, in range [0, 1], of the edge of the shape
+	ZeroLevel float64
+	// Invert maps the inside of the shape to bright levels rather than dark
+	Invert bool
+	// BitDepth is the number of bits per pixel, either 8 or 16
+	BitDepth int
+}
+
+// DrawEncoding is the Encoding that Draw uses, for decoding its images
+var DrawEncoding = Encoding{Spread: 127.5, ZeroLevel: 127.0 / 255, BitDepth: 8}
+
+// Validate returns an error if the Encoding cannot be used
+func (e Encoding) Validate() error {
+	if err := e.validateMapping(); err != nil {
+		return err
+	}
+	if e.BitDepth != 8 && e.BitDepth != 16 {
+		return errors.New("encoding bit depth must be 8 or 16")
+	}
+	return nil
+}
+
+// validateMapping validates the parts of the Encoding that map between distances and gray levels
+func (e Encoding) validateMapping() error {
+	if !(e.Spread > 0) || math.IsInf(e.Spread, 0) {
+		return errors.New("encoding spread must be positive")
+	}
+	if !(e.ZeroLevel >= 0 && e.ZeroLevel <= 1) {
+		return errors.New("encoding zero level must be in range [0, 1]")
+	}
+	return nil
+}
+
+// Encode maps a distance to a gray level in range [0, 1]
+func (e Encoding) Encode(d float64) float64 {
+	if e.Invert {
+		d = -d
+	}
+	return math.Max(0, math.Min(1, e.ZeroLevel+d/(2*e.Spread)))
+}
+
+// Decode maps a gray level in range [0, 1] back to a distance. Distances that were beyond the
+// encoded range decode to its ends.
+func (e Encoding) Decode(v float64) float64 {
+	d := (v - e.ZeroLevel) * 2 * e.Spread
+	if e.Invert {
+		d = -d
+	}
+	return d
+}
+
+// DrawEncoded returns a grayscale representation of a Signed-Distance-Field using the given
+// Encoding. The image is an *image.Gray for a BitDepth of 8 and an *image.Gray16 for 16.
+func (sdf *SDF) DrawEncoded(e Encoding) (image.Image, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	bounds := image.Rect(0, 0, sdf.Width, sdf.Height)
+	if e.BitDepth == 16 {
+		gray := image.NewGray16(bounds)
+		for y := 0; y < sdf.Height; y++ {
+			for x := 0; x < sdf.Width; x++ {
+				gray.SetGray16(x, y, color.Gray16{uint16(math.Round(e.Encode(sdf.At(x, y)) * math.MaxUint16))})
+			}
+		}
+		return gray, nil
+	}
+
+	gray := image.NewGray(bounds)
+	for y := 0; y < sdf.Height; y++ {
+		for x := 0; x < sdf.Width; x++ {
+			gray.SetGray(x, y, color.Gray{uint8(math.Round(e.Encode(sdf.At(x, y)) * math.MaxUint8))})
+		}
+	}
+	return gray, nil
+}
+
+// Decode reconstructs a Signed-Distance-Field from an image drawn with the given Encoding.
+// The image may be 8 or 16-bit gray, and the BitDepth of the Encoding is ignored. Colour images
+// are converted to gray.
+func Decode(img image.Image, e Encoding) (*SDF, error) {
+	if err := e.validateMapping(); err != nil {
+		return nil, err
+	}
+
+	b := img.Bounds()
+	sdf := New(b.Dx(), b.Dy())
+	for y := 0; y < sdf.Height; y++ {
+		for x := 0; x < sdf.Width; x++ {
+			gray := color.Gray16Model.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray16)
+			sdf.Set(x, y, e.Decode(float64(gray.Y)/math.MaxUint16))
+		}
+	}
+
+	return sdf, nil
+}
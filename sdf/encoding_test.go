@@ -0,0 +1,139 @@
+package sdf
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestDrawEncoded(t *testing.T) {
+	sdf := newSDF(5, 1, -8, -4, 0, 4, 8)
+
+	tests := []struct {
+		encoding Encoding
+		expected []uint8
+	}{
+		{Encoding{Spread: 4, ZeroLevel: 0.5, BitDepth: 8}, []uint8{0, 0, 128, 255, 255}},
+		{Encoding{Spread: 8, ZeroLevel: 0.5, BitDepth: 8}, []uint8{0, 64, 128, 191, 255}},
+		{Encoding{Spread: 8, ZeroLevel: 0.5, Invert: true, BitDepth: 8}, []uint8{255, 191, 128, 64, 0}},
+		{Encoding{Spread: 8, ZeroLevel: 0.75, BitDepth: 8}, []uint8{64, 128, 191, 255, 255}},
+	}
+
+	for _, tt := range tests {
+		img, err := sdf.DrawEncoded(tt.encoding)
+		if err != nil {
+			t.Fatalf("%+v: error should have been nil, not %v", tt.encoding, err)
+		}
+
+		gray, ok := img.(*image.Gray)
+		if !ok {
+			t.Fatalf("%+v: 8-bit encoding should draw an *image.Gray, not %T", tt.encoding, img)
+		}
+		for x, expected := range tt.expected {
+			if px := gray.GrayAt(x, 0).Y; px != expected {
+				t.Errorf("%+v: distance %v should be drawn at grayscale %v, not %v", tt.encoding, sdf.At(x, 0), expected, px)
+			}
+		}
+	}
+}
+
+func TestDrawEncoded16(t *testing.T) {
+	sdf := newSDF(3, 1, -16, 0, 0.001)
+
+	img, err := sdf.DrawEncoded(Encoding{Spread: 16, ZeroLevel: 0.5, BitDepth: 16})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	gray, ok := img.(*image.Gray16)
+	if !ok {
+		t.Fatalf("16-bit encoding should draw an *image.Gray16, not %T", img)
+	}
+
+	if px := gray.Gray16At(0, 0).Y; px != 0 {
+		t.Errorf("Distance of -Spread should be drawn at 0, not %v", px)
+	}
+	// 16 bits resolve differences far smaller than a pixel
+	if a, b := gray.Gray16At(1, 0).Y, gray.Gray16At(2, 0).Y; a != 32768 || b <= a {
+		t.Errorf("Distances of 0 and 0.001 should be drawn at 32768 and above it, not %v and %v", a, b)
+	}
+}
+
+func TestDrawEncodedInvalid(t *testing.T) {
+	sdf := New(2, 2)
+
+	for _, e := range []Encoding{
+		{},
+		{Spread: 0, ZeroLevel: 0.5, BitDepth: 8},
+		{Spread: -1, ZeroLevel: 0.5, BitDepth: 8},
+		{Spread: 4, ZeroLevel: 1.5, BitDepth: 8},
+		{Spread: 4, ZeroLevel: 0.5, BitDepth: 12},
+	} {
+		if img, err := sdf.DrawEncoded(e); img != nil || err == nil {
+			t.Errorf("%+v: invalid encoding should return an error and no image", e)
+		}
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	sdf := newSDF(6, 1, -5, -1.3, 0, 0.25, 2.7, 5)
+
+	for _, e := range []Encoding{
+		{Spread: 5, ZeroLevel: 0.5, BitDepth: 8},
+		{Spread: 5, ZeroLevel: 0.5, BitDepth: 16},
+		{Spread: 12, ZeroLevel: 0.25, Invert: true, BitDepth: 16},
+	} {
+		img, err := sdf.DrawEncoded(e)
+		if err != nil {
+			t.Fatalf("%+v: error should have been nil, not %v", e, err)
+		}
+
+		decoded, err := Decode(img, e)
+		if err != nil {
+			t.Fatalf("%+v: error should have been nil, not %v", e, err)
+		}
+
+		// the error is at most half of a gray level
+		levels := float64(int(1)<<uint(e.BitDepth) - 1)
+		tolerance := e.Spread / levels
+		for x := 0; x < sdf.Width; x++ {
+			if d := decoded.At(x, 0); math.Abs(d-sdf.At(x, 0)) > tolerance {
+				t.Errorf("%+v: distance %v should decode to within %v, not %v", e, sdf.At(x, 0), tolerance, d)
+			}
+		}
+	}
+}
+
+func TestDecodeDraw(t *testing.T) {
+	sdf := newSDF(4, 1, -127, -3, 0, 128)
+
+	decoded, err := Decode(sdf.Draw(), DrawEncoding)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	for x := 0; x < sdf.Width; x++ {
+		if d := decoded.At(x, 0); math.Abs(d-sdf.At(x, 0)) > 1e-9 {
+			t.Errorf("Whole distance %v drawn by Draw should decode exactly, not to %v", sdf.At(x, 0), d)
+		}
+	}
+}
+
+func TestDecodeSaturates(t *testing.T) {
+	sdf := newSDF(2, 1, -100, 100)
+	e := Encoding{Spread: 4, ZeroLevel: 0.5, BitDepth: 16}
+
+	img, _ := sdf.DrawEncoded(e)
+	decoded, _ := Decode(img, e)
+	if decoded.At(0, 0) != -4 || decoded.At(1, 0) != 4 {
+		t.Errorf("Saturated distances should decode to the ends of the range [-4, 4], not %v", decoded.Field)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+
+	if sdf, err := Decode(img, Encoding{ZeroLevel: 0.5}); sdf != nil || err == nil {
+		t.Errorf("Decode with an invalid encoding should return an error and no SDF")
+	}
+}
@@ -0,0 +1,73 @@
+package sdf
+
+// jumpFlood implements the Jump Flooding Algorithm. Each round every pixel inspects its
+// 8 neighbours at a step distance k and adopts any nearer site that they know of, with k
+// halving each round from half the grid size down to 1. A final extra round at k=1 (JFA+1)
+// mops up most of the remaining errors.
+//
+// The number of rounds is logarithmic in the grid size and every pixel within a round is
+// independent, which makes this the fastest approximate method on large grids.
+func jumpFlood(w, h int, sites []bool, r *runner) ([]point, error) {
+	nearest := make([]point, w*h)
+	for i, isSite := range sites {
+		if isSite {
+			nearest[i] = point{i % w, i / w}
+		} else {
+			nearest[i] = noPoint
+		}
+	}
+
+	next := make([]point, w*h)
+	for _, k := range jumpFloodSteps(w, h) {
+		err := r.each(h, func(y int) {
+			jumpFloodRow(y, k, w, h, nearest, next)
+		})
+		if err != nil {
+			return nil, err
+		}
+		nearest, next = next, nearest
+	}
+
+	return nearest, nil
+}
+
+// jumpFloodSteps returns the step size of each round of jump flooding over a w*h grid
+func jumpFloodSteps(w, h int) []int {
+	steps := []int{}
+	k := 1
+	for k < w || k < h {
+		k *= 2
+	}
+	for k /= 2; k >= 1; k /= 2 {
+		steps = append(steps, k)
+	}
+	return append(steps, 1)
+}
+
+// jumpFloodRow performs one round of jump flooding at step k for row y,
+// reading from the src buffer and writing to the dst buffer.
+func jumpFloodRow(y, k, w, h int, src, dst []point) {
+	for x := 0; x < w; x++ {
+		p := point{x, y}
+		best := src[y*w+x]
+
+		for dy := -k; dy <= k; dy += k {
+			for dx := -k; dx <= k; dx += k {
+				nx, ny := x+dx, y+dy
+				if nx < 0 || ny < 0 || nx >= w || ny >= h {
+					continue
+				}
+
+				candidate := src[ny*w+nx]
+				if candidate == noPoint {
+					continue
+				}
+				if best == noPoint || p.dstSq(candidate) < p.dstSq(best) {
+					best = candidate
+				}
+			}
+		}
+
+		dst[y*w+x] = best
+	}
+}
@@ -0,0 +1,141 @@
+package sdf
+
+import (
+	"math"
+	"testing"
+)
+
+// fieldOf returns an SDF holding f at the centre of each pixel
+func fieldOf(w, h int, f func(x, y float64) float64) *SDF {
+	s := New(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			s.Set(x, y, f(float64(x), float64(y)))
+		}
+	}
+	return s
+}
+
+func circleField(w, h int, cx, cy, r float64) *SDF {
+	return fieldOf(w, h, func(x, y float64) float64 {
+		return math.Hypot(x-cx, y-cy) - r
+	})
+}
+
+// boxField is the exact field of an axis-aligned box with the given corners
+func boxField(w, h int, x0, y0, x1, y1 float64) *SDF {
+	return fieldOf(w, h, func(x, y float64) float64 {
+		dx := math.Max(x0-x, x-x1)
+		dy := math.Max(y0-y, y-y1)
+		return math.Hypot(math.Max(dx, 0), math.Max(dy, 0)) + math.Min(math.Max(dx, dy), 0)
+	})
+}
+
+func assertFieldNear(t *testing.T, name string, s *SDF, tolerance float64, expected func(x, y float64) float64) {
+	t.Helper()
+	for y := 0; y < s.Height; y++ {
+		for x := 0; x < s.Width; x++ {
+			e := expected(float64(x), float64(y))
+			if f := s.At(x, y); math.Abs(f-e) > tolerance {
+				t.Errorf("%v: field value at (%v, %v) should be about %v, not %v", name, x, y, e, f)
+			}
+		}
+	}
+}
+
+func TestOffset(t *testing.T) {
+	circle := circleField(48, 40, 23.3, 19.6, 10.2)
+
+	for _, d := range []float64{4.5, -3, 0} {
+		assertFieldNear(t, "Offset", circle.Offset(d), 0.25, func(x, y float64) float64 {
+			return math.Hypot(x-23.3, y-19.6) - 10.2 - d
+		})
+	}
+}
+
+func TestOffsetRedistances(t *testing.T) {
+	// a field which is zero on a circle but isn't a distance is made into one
+	scaled := circleField(48, 40, 23.3, 19.6, 10.2)
+	for i := range scaled.Field {
+		scaled.Field[i] *= 3
+	}
+
+	assertFieldNear(t, "Offset", scaled.Offset(0), 0.25, func(x, y float64) float64 {
+		return math.Hypot(x-23.3, y-19.6) - 10.2
+	})
+}
+
+func TestOffsetShrinksToNothing(t *testing.T) {
+	shrunk := circleField(16, 16, 8, 8, 3).Offset(-5)
+
+	for i, v := range shrunk.Field {
+		if !math.IsInf(v, 1) {
+			t.Fatalf("Shrinking a shape away entirely should leave no boundary, not %v at %v", v, i)
+		}
+	}
+}
+
+func TestShell(t *testing.T) {
+	shell := circleField(48, 40, 23.3, 19.6, 12.2).Shell(4)
+
+	assertFieldNear(t, "Shell", shell, 0.25, func(x, y float64) float64 {
+		return math.Abs(math.Hypot(x-23.3, y-19.6)-12.2) - 2
+	})
+}
+
+func TestOpenRoundsConvexCorners(t *testing.T) {
+	box := boxField(32, 32, 6, 6, 25, 25)
+
+	// the opened box has corners that are quarter circles of radius 4
+	inner := boxField(32, 32, 10, 10, 21, 21)
+	rounded := func(x, y float64) float64 {
+		return inner.At(int(x), int(y)) - 4
+	}
+
+	open := box.Open(4)
+	assertFieldNear(t, "Open", open, 0.35, rounded)
+
+	if open.At(6, 6) <= 0 {
+		t.Errorf("Open should cut away the box's corner, not leave a distance of %v", open.At(6, 6))
+	}
+	if math.Abs(open.At(6, 16)) > 1e-9 {
+		t.Errorf("Open should leave the box's straight edges in place, not move them to %v", open.At(6, 16))
+	}
+}
+
+func TestCloseFillsGaps(t *testing.T) {
+	// two boxes separated by a 2 pixel gap
+	left := boxField(32, 16, 4, 4, 14, 11)
+	right := boxField(32, 16, 16, 4, 27, 11)
+	both, _ := Union(left, right)
+
+	closed := both.Close(2)
+	for y := 5; y <= 10; y++ {
+		if closed.At(15, y) >= 0 {
+			t.Errorf("Close should fill the gap between the boxes at (15, %v), not leave a distance of %v", y, closed.At(15, y))
+		}
+	}
+	if closed.At(2, 8) <= 0 {
+		t.Errorf("Close should leave the outside of the boxes outside")
+	}
+}
+
+func TestRound(t *testing.T) {
+	// an L shape has both convex and concave corners
+	vertical := boxField(40, 40, 5, 5, 15, 34)
+	horizontal := boxField(40, 40, 5, 24, 34, 34)
+	l, _ := Union(vertical, horizontal)
+
+	round := l.Round(4)
+
+	// the inside corner of the L is filled in and the outside corners cut away
+	if round.At(16, 23) >= 0 {
+		t.Errorf("Round should fill the concave corner, not leave a distance of %v", round.At(16, 23))
+	}
+	if round.At(5, 5) <= 0 {
+		t.Errorf("Round should cut away the convex corner, not leave a distance of %v", round.At(5, 5))
+	}
+	if math.Abs(round.At(5, 20)) > 0.1 {
+		t.Errorf("Round should leave straight edges in place, not move them to %v", round.At(5, 20))
+	}
+}
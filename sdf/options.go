@@ -0,0 +1,294 @@
+package sdf
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// Algorithm selects how the nearest boundary point is found for each pixel of a field
+type Algorithm int
+
+const (
+	// ExactEDT is an exact Euclidean Distance Transform which runs in linear time.
+	// This is the default algorithm.
+	ExactEDT Algorithm = iota
+	// BruteForce measures every pixel against every boundary point. It is exact but very slow.
+	BruteForce
+	// DeadReckoning is the 8-point Sequential Signed Euclidean Distance Transform (8SSEDT).
+	// It is fast but approximate, with occasional small errors far from the boundary.
+	DeadReckoning
+	// JumpFlooding is the Jump Flooding Algorithm. It is the fastest on large inputs
+	// but approximate, with occasional small errors far from the boundary.
+	JumpFlooding
+	// AntiAliased is an anti-aliased Euclidean Distance Transform (edtaa3) which uses the
+	// fractional coverage of edge pixels to measure distances to the edge with sub-pixel accuracy.
+	// It requires the Stencil to be a CoverageStencil, and treats pixels at least half covered
+	// as inside, regardless of what the Stencil's Within method reports.
+	AntiAliased
+)
+
+var algorithmNames = map[Algorithm]string{
+	ExactEDT:      "ExactEDT",
+	BruteForce:    "BruteForce",
+	DeadReckoning: "DeadReckoning",
+	JumpFlooding:  "JumpFlooding",
+	AntiAliased:   "AntiAliased",
+}
+
+// String returns the name of the Algorithm
+func (a Algorithm) String() string {
+	if name, ok := algorithmNames[a]; ok {
+		return name
+	}
+	return "Algorithm(?)"
+}
+
+// transform is a distance transform which finds the nearest site for every pixel of a w*h grid
+type transform func(w, h int, sites []bool, r *runner) ([]point, error)
+
+// transform returns the distance transform implementing the Algorithm
+// along with the number of work items it reports progress for on a w*h grid
+func (a Algorithm) transform(w, h int) (transform, int, error) {
+	switch a {
+	case ExactEDT:
+		return edt, w + h, nil
+	case BruteForce:
+		return bruteForce, h, nil
+	case DeadReckoning:
+		return deadReckoning, 2 * h, nil
+	case JumpFlooding:
+		return jumpFlood, len(jumpFloodSteps(w, h)) * h, nil
+	}
+	return nil, 0, errors.New("unknown distance transform algorithm")
+}
+
+// BoundaryMode defines where the boundary of a Stencil lies, from which distances are measured
+type BoundaryMode int
+
+const (
+	// PixelBoundary places the boundary on the pixels within the stencil that have adjacent
+	// pixels outside the stencil (or lie on the border of the image). Distances are measured to
+	// the centres of those pixels so the zero iso-line sits half a pixel inside the true edge.
+	// This is the default.
+	PixelBoundary BoundaryMode = iota
+	// EdgeBoundary places the boundary on the edge between pixels inside and outside the stencil.
+	// Distances approximate the distance to that edge as the distance to the centre of the nearest
+	// pixel on the other side less half a pixel, which is exact only for edges aligned with the
+	// axes. Inside and outside distances are unbiased and inverting the stencil exactly negates
+	// the field. The border of the image is not a boundary. The nearest boundary point of each pixel is the nearest pixel on the other side
+	// of the edge.
+	EdgeBoundary
+)
+
+// ErrNoBoundary is returned when Options.RequireBoundary is set and the stencil has no boundary
+var ErrNoBoundary = errors.New("stencil has no boundary")
+
+// Options configures how a DisplacementField is calculated.
+// The zero value is valid and matches the behaviour of Calculate.
+type Options struct {
+	// Algorithm selects the distance transform, trading speed against accuracy
+	Algorithm Algorithm
+
+	// Boundary selects where the boundary of the stencil lies.
+	// It is ignored by the AntiAliased algorithm which always measures to the sub-pixel edge.
+	Boundary BoundaryMode
+
+	// MaxDistance, if greater than zero, clamps distances to the range [-MaxDistance, MaxDistance].
+	// Otherwise pixels with no boundary to measure to are at a distance of +Inf or -Inf.
+	MaxDistance float64
+
+	// RequireBoundary causes ErrNoBoundary to be returned, instead of a field, if any pixel has no
+	// boundary to measure to. With the PixelBoundary this happens only for empty stencils, as the
+	// border of the image bounds full stencils, but with the EdgeBoundary full stencils do too.
+	RequireBoundary bool
+
+	// Workers is the number of goroutines to spread the work across.
+	// Zero or less uses runtime.GOMAXPROCS. With more than one worker the Stencil is read
+	// concurrently, so it must be safe for concurrent use.
+	Workers int
+
+	// Progress, if not nil, is called each time a unit of work completes with the number of
+	// units done so far out of the total. Calls are serialised but may come from any goroutine.
+	// The total may grow part way through if an algorithm needs to make extra passes.
+	Progress func(done, total int)
+}
+
+// CalculateWithOptions calculates a new DisplacementField from the given Stencil according to opts
+func CalculateWithOptions(s Stencil, opts Options) (*DisplacementField, error) {
+	return CalculateContext(context.Background(), s, opts)
+}
+
+// CalculateContext calculates a new DisplacementField from the given Stencil according to opts.
+// The calculation is abandoned, returning ctx.Err(), as soon as possible once ctx is done.
+// The result is identical regardless of how many Workers are used.
+func CalculateContext(ctx context.Context, s Stencil, opts Options) (*DisplacementField, error) {
+	w, h := s.Size()
+	if opts.Algorithm == AntiAliased {
+		return calculateAntiAliased(ctx, s, opts)
+	}
+
+	transform, work, err := opts.Algorithm.transform(w, h)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Boundary {
+	case PixelBoundary:
+		return calculatePixelBoundary(ctx, s, opts, transform, work)
+	case EdgeBoundary:
+		return calculateEdgeBoundary(ctx, s, opts, transform, work)
+	}
+	return nil, errors.New("unknown boundary mode")
+}
+
+// calculatePixelBoundary calculates a new DisplacementField with a PixelBoundary
+func calculatePixelBoundary(ctx context.Context, s Stencil, opts Options, transform transform, work int) (*DisplacementField, error) {
+	// finding the boundaries and then signing the distances each take a pass over the rows
+	w, h := s.Size()
+	r := newRunner(ctx, opts.Workers, opts.Progress, h+work+h)
+
+	boundary, err := findBoundaries(s, r)
+	if err != nil {
+		return nil, err
+	}
+
+	nearest, err := transform(w, h, boundary, r)
+	if err != nil {
+		return nil, err
+	}
+
+	df := DisplacementField{
+		New(w, h),
+		nearest,
+	}
+
+	err = r.each(h, func(y int) {
+		for x := 0; x < w; x++ {
+			dst := math.Inf(1)
+			if pt := df.boundaryPts[y*w+x]; pt != noPoint {
+				dst = math.Sqrt(point{x, y}.dstSq(pt))
+			}
+
+			// use -ve sign if we are inside and +ve if outside
+			if s.Within(x, y) {
+				dst = -dst
+			}
+
+			df.Set(x, y, dst)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.finish(&df)
+}
+
+// calculateEdgeBoundary calculates a new DisplacementField with an EdgeBoundary
+func calculateEdgeBoundary(ctx context.Context, s Stencil, opts Options, transform transform, work int) (*DisplacementField, error) {
+	// reading the stencil and then signing the distances each take a pass over the rows,
+	// between which the inside and outside each need a transform
+	w, h := s.Size()
+	r := newRunner(ctx, opts.Workers, opts.Progress, h+2*work+h)
+
+	inside := make([]bool, w*h)
+	err := r.each(h, func(y int) {
+		for x := 0; x < w; x++ {
+			inside[y*w+x] = s.Within(x, y)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outside := make([]bool, w*h)
+	for i := range inside {
+		outside[i] = !inside[i]
+	}
+
+	// pixels outside measure to the nearest pixel inside, and vice-versa
+	nearestInside, err := transform(w, h, inside, r)
+	if err != nil {
+		return nil, err
+	}
+
+	nearestOutside, err := transform(w, h, outside, r)
+	if err != nil {
+		return nil, err
+	}
+
+	df := DisplacementField{
+		New(w, h),
+		nearestInside,
+	}
+
+	err = r.each(h, func(y int) {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			if inside[i] {
+				df.boundaryPts[i] = nearestOutside[i]
+			}
+
+			// the edge lies half way between the centres of adjacent inside and outside pixels
+			dst := math.Inf(1)
+			if pt := df.boundaryPts[i]; pt != noPoint {
+				dst = math.Sqrt(point{x, y}.dstSq(pt)) - 0.5
+			}
+
+			// use -ve sign if we are inside and +ve if outside
+			if inside[i] {
+				dst = -dst
+			}
+
+			df.Set(x, y, dst)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.finish(&df)
+}
+
+// calculateAntiAliased calculates a new DisplacementField using the AntiAliased algorithm
+func calculateAntiAliased(ctx context.Context, s Stencil, opts Options) (*DisplacementField, error) {
+	cs, ok := s.(CoverageStencil)
+	if !ok {
+		return nil, errors.New("the AntiAliased algorithm requires a CoverageStencil")
+	}
+
+	// reading the coverage and combining the results each take a pass over the rows,
+	// between which the inside and outside each take a pass for gradients and two sweeps
+	w, h := s.Size()
+	r := newRunner(ctx, opts.Workers, opts.Progress, h+2*(h+2*h)+h)
+
+	field, nearest, err := antiAliased(cs, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.finish(&DisplacementField{
+		&SDF{Field: field, Width: w, Height: h},
+		nearest,
+	})
+}
+
+// finish applies the options concerning pixels that are far from, or have no, boundary
+func (opts Options) finish(df *DisplacementField) (*DisplacementField, error) {
+	if opts.RequireBoundary {
+		for _, pt := range df.boundaryPts {
+			if pt == noPoint {
+				return nil, ErrNoBoundary
+			}
+		}
+	}
+
+	if opts.MaxDistance > 0 {
+		for i, v := range df.Field {
+			df.Field[i] = math.Max(-opts.MaxDistance, math.Min(opts.MaxDistance, v))
+		}
+	}
+
+	return df, nil
+}
@@ -0,0 +1,284 @@
+package sdf
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var allAlgorithms = []Algorithm{ExactEDT, BruteForce, DeadReckoning, JumpFlooding}
+
+func TestAlgorithmString(t *testing.T) {
+	if s := JumpFlooding.String(); s != "JumpFlooding" {
+		t.Errorf("JumpFlooding should be named JumpFlooding, not %v", s)
+	}
+
+	if s := Algorithm(-1).String(); s != "Algorithm(?)" {
+		t.Errorf("Unknown algorithms should be named Algorithm(?), not %v", s)
+	}
+}
+
+func TestCalculateWithOptionsUnknownAlgorithm(t *testing.T) {
+	df, err := CalculateWithOptions(stubStencil{}, Options{Algorithm: Algorithm(-1)})
+
+	if df != nil {
+		t.Errorf("CalculateWithOptions should not return a result for an unknown algorithm")
+	}
+
+	if err == nil {
+		t.Errorf("CalculateWithOptions should return an error for an unknown algorithm")
+	}
+}
+
+func TestCalculateWithOptionsAlgorithms(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	stencils := []Stencil{
+		stubStencil{},
+		circleStencil(64, 48, 30, 20, 15),
+		randomStencil(rng, 50, 37, 0.01),
+		randomStencil(rng, 23, 61, 0.5),
+	}
+
+	// the approximate algorithms may pick a slightly further boundary point, but never by much
+	tolerances := map[Algorithm]float64{
+		ExactEDT:      0,
+		BruteForce:    0,
+		DeadReckoning: 1.5,
+		JumpFlooding:  1.5,
+	}
+
+	for _, s := range stencils {
+		w, h := s.Size()
+		boundary := boundaryMask(s)
+		exact := Calculate(s)
+
+		for _, alg := range allAlgorithms {
+			df, err := CalculateWithOptions(s, Options{Algorithm: alg})
+			if err != nil {
+				t.Fatalf("%v: error should have been nil, not %v", alg, err)
+			}
+
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					f := df.At(x, y)
+					if math.Signbit(f) != math.Signbit(exact.At(x, y)) {
+						t.Errorf("%v: field value at (%v, %v) should have the same sign as %v, not %v", alg, x, y, exact.At(x, y), f)
+					}
+
+					if diff := math.Abs(f - exact.At(x, y)); diff > tolerances[alg] {
+						t.Errorf("%v: field value at (%v, %v) should be within %v of %v, not %v", alg, x, y, tolerances[alg], exact.At(x, y), f)
+					}
+
+					nx, ny := df.NearestBoundaryAt(x, y)
+					if !boundary[ny*w+nx] {
+						t.Errorf("%v: nearest boundary at (%v, %v) is (%v, %v) which is not a boundary point", alg, x, y, nx, ny)
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkCalculateWithOptions(b *testing.B) {
+	s := circleStencil(512, 512, 256, 256, 200)
+	for _, alg := range allAlgorithms {
+		if alg == BruteForce {
+			continue
+		}
+		b.Run(alg.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				CalculateWithOptions(s, Options{Algorithm: alg})
+			}
+		})
+	}
+}
+
+func TestCalculateWithOptionsUnknownBoundaryMode(t *testing.T) {
+	df, err := CalculateWithOptions(stubStencil{}, Options{Boundary: BoundaryMode(-1)})
+
+	if df != nil {
+		t.Errorf("CalculateWithOptions should not return a result for an unknown boundary mode")
+	}
+
+	if err == nil {
+		t.Errorf("CalculateWithOptions should return an error for an unknown boundary mode")
+	}
+}
+
+func TestEdgeBoundaryStraightEdge(t *testing.T) {
+	// a vertical edge lies between columns 2 and 3
+	s := maskStencil{6, 3, make([]bool, 6*3)}
+	for i := range s.mask {
+		s.mask[i] = i%6 < 3
+	}
+
+	df, err := CalculateWithOptions(s, Options{Boundary: EdgeBoundary})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 6; x++ {
+			expected := float64(x) - 2.5
+			if f := df.At(x, y); f != expected {
+				t.Errorf("Field value at (%v, %v) should equal %v, not %v", x, y, expected, f)
+			}
+
+			nx, ny := df.NearestBoundaryAt(x, y)
+			if s.Within(nx, ny) == s.Within(x, y) {
+				t.Errorf("Nearest boundary at (%v, %v) should be on the other side of the edge, not (%v, %v)", x, y, nx, ny)
+			}
+		}
+	}
+}
+
+func TestEdgeBoundaryIsSymmetric(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	s := randomStencil(rng, 41, 29, 0.3)
+	inverted := maskStencil{s.w, s.h, make([]bool, len(s.mask))}
+	for i := range s.mask {
+		inverted.mask[i] = !s.mask[i]
+	}
+
+	for _, alg := range allAlgorithms {
+		opts := Options{Algorithm: alg, Boundary: EdgeBoundary}
+		df, err := CalculateWithOptions(s, opts)
+		if err != nil {
+			t.Fatalf("%v: error should have been nil, not %v", alg, err)
+		}
+
+		invertedDF, err := CalculateWithOptions(inverted, opts)
+		if err != nil {
+			t.Fatalf("%v: error should have been nil, not %v", alg, err)
+		}
+
+		for i := range df.Field {
+			if df.Field[i] != -invertedDF.Field[i] {
+				t.Fatalf("%v: field value at index [%v] of the inverted stencil should equal %v, not %v", alg, i, -df.Field[i], invertedDF.Field[i])
+			}
+		}
+	}
+}
+
+func TestEdgeBoundaryLerpReproducesStencil(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	stencils := []maskStencil{
+		circleStencil(33, 27, 16, 13, 9.5),
+		randomStencil(rng, 30, 30, 0.5),
+	}
+
+	for _, s := range stencils {
+		df, err := CalculateWithOptions(s, Options{Boundary: EdgeBoundary})
+		if err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+
+		for _, tween := range []float64{0, 0.3, 1} {
+			lerp, err := Lerp(df.SDF, df.SDF, tween)
+			if err != nil {
+				t.Fatalf("Error should have been nil, not %v", err)
+			}
+
+			implicit := ImplicitSurfaceStencil{SDF: lerp, Threshold: 0}
+			for y := 0; y < s.h; y++ {
+				for x := 0; x < s.w; x++ {
+					if implicit.Within(x, y) != s.Within(x, y) {
+						t.Errorf("Lerp at %v of a shape with itself should reproduce the stencil at (%v, %v)", tween, x, y)
+					}
+
+					// the field is never zero so the edge is unambiguous at any threshold near 0
+					if f := lerp.At(x, y); math.Abs(f) < 0.5 {
+						t.Errorf("Field value at (%v, %v) should be at least half a pixel from the edge, not %v", x, y, f)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCalculateWithOptionsNoBoundary(t *testing.T) {
+	empty := maskStencil{4, 3, make([]bool, 4*3)}
+	full := maskStencil{4, 3, make([]bool, 4*3)}
+	for i := range full.mask {
+		full.mask[i] = true
+	}
+
+	tests := []struct {
+		name     string
+		s        Stencil
+		boundary BoundaryMode
+		expected float64
+	}{
+		{"empty stencil with pixel boundary", empty, PixelBoundary, math.Inf(1)},
+		{"empty stencil with edge boundary", empty, EdgeBoundary, math.Inf(1)},
+		{"full stencil with edge boundary", full, EdgeBoundary, math.Inf(-1)},
+	}
+
+	for _, tt := range tests {
+		for _, alg := range allAlgorithms {
+			df, err := CalculateWithOptions(tt.s, Options{Algorithm: alg, Boundary: tt.boundary})
+			if err != nil {
+				t.Fatalf("%v, %v: error should have been nil, not %v", tt.name, alg, err)
+			}
+
+			for i, f := range df.Field {
+				if f != tt.expected {
+					t.Errorf("%v, %v: field value at index [%v] should be %v, not %v", tt.name, alg, i, tt.expected, f)
+				}
+			}
+
+			clamped, err := CalculateWithOptions(tt.s, Options{Algorithm: alg, Boundary: tt.boundary, MaxDistance: 7})
+			if err != nil {
+				t.Fatalf("%v, %v: error should have been nil, not %v", tt.name, alg, err)
+			}
+
+			for i, f := range clamped.Field {
+				if f != math.Copysign(7, tt.expected) {
+					t.Errorf("%v, %v: field value at index [%v] should be clamped to %v, not %v", tt.name, alg, i, math.Copysign(7, tt.expected), f)
+				}
+			}
+
+			res, err := CalculateWithOptions(tt.s, Options{Algorithm: alg, Boundary: tt.boundary, RequireBoundary: true})
+			if res != nil || err != ErrNoBoundary {
+				t.Errorf("%v, %v: should return ErrNoBoundary and no result when a boundary is required, not %v", tt.name, alg, err)
+			}
+		}
+	}
+}
+
+func TestAntiAliasedNoBoundary(t *testing.T) {
+	empty := maskCoverage{w: 4, h: 3, coverage: func(x, y int) float64 { return 0 }}
+
+	df, err := CalculateWithOptions(empty, Options{Algorithm: AntiAliased})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	for i, f := range df.Field {
+		if !math.IsInf(f, 1) {
+			t.Errorf("Field value at index [%v] of an empty stencil should be +Inf, not %v", i, f)
+		}
+	}
+
+	res, err := CalculateWithOptions(empty, Options{Algorithm: AntiAliased, RequireBoundary: true})
+	if res != nil || err != ErrNoBoundary {
+		t.Errorf("Should return ErrNoBoundary and no result when a boundary is required, not %v", err)
+	}
+}
+
+func TestCalculateWithOptionsMaxDistance(t *testing.T) {
+	s := circleStencil(40, 40, 20, 20, 4)
+	exact := Calculate(s)
+
+	df, err := CalculateWithOptions(s, Options{MaxDistance: 5})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	for i, f := range df.Field {
+		expected := math.Max(-5, math.Min(5, exact.Field[i]))
+		if f != expected {
+			t.Errorf("Field value at index [%v] should be clamped to %v, not %v", i, expected, f)
+		}
+	}
+}
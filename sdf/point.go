@@ -6,6 +6,9 @@ type point struct {
 	x, y int
 }
 
+// noPoint marks the absence of a point, such as when there is no nearest boundary point
+var noPoint = point{-1, -1}
+
 func (p point) dstSq(q point) float64 {
 	return float64((p.x-q.x)*(p.x-q.x) + (p.y-q.y)*(p.y-q.y))
 }
@@ -0,0 +1,134 @@
+package sdf
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// RenderOptions controls how Render converts a field into an anti-aliased mask
+type RenderOptions struct {
+	// Scale is the size of the mask relative to the field. Zero renders at the size of the field.
+	Scale float64
+	// Width is the width, in pixels of the mask, over which coverage ramps from 1 to 0 across
+	// the edge. Zero uses a width of 1 pixel.
+	Width float64
+	// Threshold is the distance of the edge, as for ImplicitSurfaceStencil
+	Threshold float64
+	// Sampler interpolates the field between its pixels
+	Sampler Sampler
+}
+
+// defaults returns the options with zero values replaced by their defaults
+func (o RenderOptions) defaults() RenderOptions {
+	if !(o.Scale > 0) {
+		o.Scale = 1
+	}
+	if !(o.Width > 0) {
+		o.Width = 1
+	}
+	return o
+}
+
+// Render returns an alpha mask of the field's shape, opaque inside and transparent outside.
+// Coverage ramps smoothly across the edge over o.Width pixels of the mask. How far that is in
+// the field is estimated from the field's rate of change per mask pixel, so edges keep the same
+// softness at any scale and even where the field is not a true distance field, such as a blend
+// of two fields.
+func (sdf *SDF) Render(o RenderOptions) *image.Alpha {
+	o = o.defaults()
+	w := int(math.Ceil(float64(sdf.Width) * o.Scale))
+	h := int(math.Ceil(float64(sdf.Height) * o.Scale))
+	img := image.NewAlpha(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// map the mask pixel centre back into field coordinates
+			coverage := o.coverage(sdf, (float64(x)+0.5)/o.Scale, (float64(y)+0.5)/o.Scale)
+			img.SetAlpha(x, y, color.Alpha{uint8(math.Round(coverage * math.MaxUint8))})
+		}
+	}
+
+	return img
+}
+
+// coverage returns the fraction of the mask pixel centred at (x, y) in field coordinates that
+// lies inside the edge
+func (o RenderOptions) coverage(sdf *SDF, x, y float64) float64 {
+	d, dx, dy := o.Sampler.sample(sdf, x, y, true)
+	d -= o.Threshold
+
+	// the change in distance from one mask pixel to the next along the gradient, much as a
+	// shader estimates it with screen-space derivatives
+	perPixel := math.Hypot(dx, dy) / o.Scale
+	width := o.Width * perPixel
+	if math.IsNaN(width) || math.IsInf(width, 0) {
+		width = 0
+	}
+
+	return Coverage(d, width)
+}
+
+// Coverage returns the fraction, in range [0, 1], of a pixel that lies inside an edge given the
+// signed distance d from the pixel's centre to the edge. Coverage ramps smoothly from 1 to 0 over
+// the given width, centred on the edge. A width of zero or less gives a hard edge.
+func Coverage(d, width float64) float64 {
+	if !(width > 0) {
+		if d <= 0 {
+			return 1
+		}
+		return 0
+	}
+
+	// a smooth Hermite ramp, as GLSL's smoothstep
+	t := math.Max(0, math.Min(1, (d+width/2)/width))
+	return 1 - t*t*(3-2*t)
+}
+
+// SmoothSurfaceStencil is an ImplicitSurfaceStencil with anti-aliased edges. It is a
+// CoverageStencil whose coverage ramps across the edge over Width pixels, as Render does.
+type SmoothSurfaceStencil struct {
+	SDF       *SDF
+	Threshold float64
+	// Width is the width in pixels over which coverage ramps across the edge. Zero uses 1 pixel.
+	Width float64
+}
+
+// Within predicates whether the given coordinate is inside or outside of the stencil surface
+func (s SmoothSurfaceStencil) Within(x, y int) bool {
+	return s.SDF.At(x, y) <= s.Threshold
+}
+
+// Coverage returns the fraction, in range [0, 1], of the given pixel within the surface
+func (s SmoothSurfaceStencil) Coverage(x, y int) float64 {
+	o := RenderOptions{Width: s.Width, Threshold: s.Threshold}.defaults()
+	return o.coverage(s.SDF, float64(x)+0.5, float64(y)+0.5)
+}
+
+// Size returns the width and height of the SmoothSurfaceStencil
+func (s SmoothSurfaceStencil) Size() (int, int) {
+	return s.SDF.Width, s.SDF.Height
+}
+
+// DrawCoverageImage is the anti-aliased counterpart of DrawStencilImage. Each pixel blends the
+// source image over the bg color in proportion to the stencil's coverage of the pixel.
+func DrawCoverageImage(s CoverageStencil, srcImg image.Image, bg color.Color) *image.RGBA {
+	w, h := s.Size()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	br, bgg, bb, ba := bg.RGBA()
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := math.Max(0, math.Min(1, s.Coverage(x, y)))
+			sr, sg, sb, sa := srcImg.At(x, y).RGBA()
+
+			// colors are alpha-premultiplied so blending each channel linearly is exact
+			blend := func(src, dst uint32) uint16 {
+				return uint16(math.Round(float64(src)*c + float64(dst)*(1-c)))
+			}
+			img.Set(x, y, color.RGBA64{blend(sr, br), blend(sg, bgg), blend(sb, bb), blend(sa, ba)})
+		}
+	}
+
+	return img
+}
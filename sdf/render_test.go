@@ -0,0 +1,152 @@
+package sdf
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// edgeField returns a field of a vertical edge at x=edge, in image coordinates, scaled by k
+func edgeField(w, h int, edge, k float64) *SDF {
+	return fieldOf(w, h, func(x, y float64) float64 {
+		return k * (x + 0.5 - edge)
+	})
+}
+
+func TestRender(t *testing.T) {
+	img := edgeField(10, 2, 4.75, 1).Render(RenderOptions{})
+	if size := img.Bounds().Size(); size != (image.Point{10, 2}) {
+		t.Fatalf("Mask size should be 10x2, not %v", size)
+	}
+
+	// the centre of pixel 4 lies a quarter pixel inside the edge, a quarter of the way up the ramp
+	for x, expected := range []uint8{255, 255, 255, 255, 215, 0, 0, 0, 0, 0} {
+		if a := img.AlphaAt(x, 1).A; a != expected {
+			t.Errorf("Alpha of pixel %v should be %v, not %v", x, expected, a)
+		}
+	}
+}
+
+func TestRenderWidth(t *testing.T) {
+	img := edgeField(20, 1, 10, 1).Render(RenderOptions{Width: 6})
+	partial := 0
+	for x := 0; x < 20; x++ {
+		if a := img.AlphaAt(x, 0).A; a != 0 && a != 255 {
+			partial++
+		}
+	}
+	if partial != 6 {
+		t.Errorf("A 6 pixel wide edge should partially cover 6 pixels, not %v", partial)
+	}
+}
+
+func TestRenderScaleKeepsEdgeWidth(t *testing.T) {
+	for _, scale := range []float64{1, 3, 8} {
+		img := edgeField(12, 1, 6.3, 1).Render(RenderOptions{Scale: scale, Width: 2})
+		if w := img.Bounds().Dx(); w != int(math.Ceil(12*scale)) {
+			t.Errorf("Mask at scale %v should be %v pixels wide, not %v", scale, math.Ceil(12*scale), w)
+		}
+
+		partial := 0
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			if a := img.AlphaAt(x, 0).A; a != 0 && a != 255 {
+				partial++
+			}
+		}
+		if partial != 2 {
+			t.Errorf("A 2 pixel wide edge at scale %v should partially cover 2 pixels, not %v", scale, partial)
+		}
+	}
+}
+
+func TestRenderNonMetricField(t *testing.T) {
+	// a field that changes 3 times faster than distance still has an edge of the same width
+	expected := edgeField(10, 1, 4.6, 1).Render(RenderOptions{Scale: 2, Width: 3})
+	actual := edgeField(10, 1, 4.6, 3).Render(RenderOptions{Scale: 2, Width: 3})
+	for x := 0; x < expected.Bounds().Dx(); x++ {
+		e, a := expected.AlphaAt(x, 0).A, actual.AlphaAt(x, 0).A
+		if d := int(e) - int(a); d < -1 || d > 1 {
+			t.Errorf("Alpha of pixel %v should be %v, not %v", x, e, a)
+		}
+	}
+}
+
+func TestRenderThreshold(t *testing.T) {
+	img := edgeField(10, 1, 4, 1).Render(RenderOptions{Threshold: 2, Width: 0.5})
+	if a := img.AlphaAt(5, 0).A; a != 255 {
+		t.Errorf("Alpha inside the threshold should be 255, not %v", a)
+	}
+	if a := img.AlphaAt(6, 0).A; a != 0 {
+		t.Errorf("Alpha outside the threshold should be 0, not %v", a)
+	}
+}
+
+func TestRenderFlatFields(t *testing.T) {
+	for _, test := range []struct {
+		d     float64
+		alpha uint8
+	}{
+		{-1, 255},
+		{1, 0},
+		{math.Inf(-1), 255},
+		{math.Inf(1), 0},
+	} {
+		f := fieldOf(3, 3, func(x, y float64) float64 { return test.d })
+		if a := f.Render(RenderOptions{}).AlphaAt(1, 1).A; a != test.alpha {
+			t.Errorf("Alpha of a field of %v should be %v, not %v", test.d, test.alpha, a)
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	for _, test := range []struct {
+		d, width, expected float64
+	}{
+		{-1, 2, 1},
+		{0, 2, 0.5},
+		{1, 2, 0},
+		{-0.5, 2, 0.84375},
+		{0.5, 2, 0.15625},
+		{0, 0, 1},
+		{0.1, 0, 0},
+		{-0.1, -1, 1},
+	} {
+		if c := Coverage(test.d, test.width); math.Abs(c-test.expected) > 1e-12 {
+			t.Errorf("Coverage at %v over %v should be %v, not %v", test.d, test.width, test.expected, c)
+		}
+	}
+}
+
+func TestSmoothSurfaceStencil(t *testing.T) {
+	s := SmoothSurfaceStencil{SDF: edgeField(10, 1, 4.75, 1)}
+	if w, h := s.Size(); w != 10 || h != 1 {
+		t.Errorf("Size should be 10x1, not %vx%v", w, h)
+	}
+	if !s.Within(4, 0) || s.Within(5, 0) {
+		t.Errorf("Only pixels left of the edge should be within the stencil")
+	}
+	for x, expected := range map[int]float64{3: 1, 4: 0.84375, 5: 0} {
+		if c := s.Coverage(x, 0); math.Abs(c-expected) > 1e-9 {
+			t.Errorf("Coverage of pixel %v should be %v, not %v", x, expected, c)
+		}
+	}
+}
+
+func TestDrawCoverageImage(t *testing.T) {
+	s := maskCoverage{w: 3, h: 1, coverage: func(x, y int) float64 { return float64(x) / 2 }}
+	src := image.NewUniform(color.White)
+
+	img := DrawCoverageImage(s, src, color.Black)
+	for x, expected := range []uint8{0, 128, 255} {
+		if c := img.RGBAAt(x, 0); c != (color.RGBA{expected, expected, expected, 255}) {
+			t.Errorf("Pixel %v should be gray %v, not %v", x, expected, c)
+		}
+	}
+
+	// blending over a transparent background leaves partially transparent edges
+	img = DrawCoverageImage(s, src, color.Transparent)
+	if c := img.RGBAAt(1, 0); c != (color.RGBA{128, 128, 128, 128}) {
+		t.Errorf("Half covered pixel should be half transparent white, not %v", c)
+	}
+}
@@ -0,0 +1,100 @@
+package sdf
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// runner executes the stages of a calculation, spreading independent work items across
+// goroutines while honouring cancellation and reporting progress as items complete.
+type runner struct {
+	ctx      context.Context
+	workers  int
+	progress func(done, total int)
+
+	mu    sync.Mutex
+	done  int
+	total int
+}
+
+func newRunner(ctx context.Context, workers int, progress func(done, total int), total int) *runner {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return &runner{
+		ctx:      ctx,
+		workers:  workers,
+		progress: progress,
+		total:    total,
+	}
+}
+
+// completed records that n more work items have finished and reports progress
+func (r *runner) completed(n int) {
+	if r.progress == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.done += n
+	r.progress(r.done, r.total)
+}
+
+// grow adds n more work items to the total, for when the amount of work is not known upfront
+func (r *runner) grow(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.total += n
+}
+
+// sequence runs fn for each i in [0, n) in order on the calling goroutine
+func (r *runner) sequence(n int, fn func(i int)) error {
+	for i := 0; i < n; i++ {
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
+		fn(i)
+		r.completed(1)
+	}
+
+	return r.ctx.Err()
+}
+
+// each runs fn for each i in [0, n) across the runner's workers. fn must be safe to call
+// concurrently for distinct values of i. Once cancelled no further items are started.
+func (r *runner) each(n int, fn func(i int)) error {
+	workers := r.workers
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		return r.sequence(n, fn)
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for r.ctx.Err() == nil {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				fn(i)
+				r.completed(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+	return r.ctx.Err()
+}
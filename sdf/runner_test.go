@@ -0,0 +1,95 @@
+package sdf
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCalculateContextMatchesSerial(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	s := randomStencil(rng, 97, 83, 0.05)
+
+	for _, alg := range allAlgorithms {
+		serial, err := CalculateContext(context.Background(), s, Options{Algorithm: alg, Workers: 1})
+		if err != nil {
+			t.Fatalf("%v: error should have been nil, not %v", alg, err)
+		}
+
+		parallel, err := CalculateContext(context.Background(), s, Options{Algorithm: alg, Workers: 8})
+		if err != nil {
+			t.Fatalf("%v: error should have been nil, not %v", alg, err)
+		}
+
+		if !reflect.DeepEqual(serial.Field, parallel.Field) {
+			t.Errorf("%v: parallel field should be identical to the serial field", alg)
+		}
+
+		if !reflect.DeepEqual(serial.boundaryPts, parallel.boundaryPts) {
+			t.Errorf("%v: parallel nearest boundary points should be identical to the serial ones", alg)
+		}
+	}
+}
+
+func TestCalculateContextProgress(t *testing.T) {
+	for _, alg := range allAlgorithms {
+		lastDone, lastTotal := 0, 0
+		opts := Options{
+			Algorithm: alg,
+			Workers:   4,
+			Progress: func(done, total int) {
+				if done != lastDone+1 {
+					t.Errorf("%v: progress should advance one unit at a time, not from %v to %v", alg, lastDone, done)
+				}
+				if done > total {
+					t.Errorf("%v: progress of %v should never exceed the total of %v", alg, done, total)
+				}
+				lastDone, lastTotal = done, total
+			},
+		}
+
+		_, err := CalculateContext(context.Background(), circleStencil(30, 20, 10, 10, 5), opts)
+		if err != nil {
+			t.Fatalf("%v: error should have been nil, not %v", alg, err)
+		}
+
+		if lastDone == 0 || lastDone != lastTotal {
+			t.Errorf("%v: progress should finish with done equal to the total, not %v of %v", alg, lastDone, lastTotal)
+		}
+	}
+}
+
+func TestCalculateContextCancelled(t *testing.T) {
+	for _, alg := range allAlgorithms {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		opts := Options{
+			Algorithm: alg,
+			Workers:   1,
+			Progress: func(done, total int) {
+				// cancel part way through the calculation
+				calls++
+				if calls == 10 {
+					cancel()
+				}
+			},
+		}
+
+		df, err := CalculateContext(ctx, circleStencil(30, 20, 10, 10, 5), opts)
+
+		if df != nil {
+			t.Errorf("%v: CalculateContext should not return a result once cancelled", alg)
+		}
+
+		if err != context.Canceled {
+			t.Errorf("%v: CalculateContext should return context.Canceled once cancelled, not %v", alg, err)
+		}
+
+		if calls != 10 {
+			t.Errorf("%v: no further work should be done once cancelled, but progress was reported %v times", alg, calls)
+		}
+
+		cancel()
+	}
+}
@@ -0,0 +1,182 @@
+package sdf
+
+import "math"
+
+// Filter selects how a field is interpolated between pixel centres
+type Filter int
+
+const (
+	// Bilinear interpolates linearly between the nearest 2x2 pixels. This is the default.
+	Bilinear Filter = iota
+	// Bicubic interpolates between the nearest 4x4 pixels with a Catmull-Rom spline, which is
+	// smoother and has a continuous gradient.
+	Bicubic
+)
+
+// Border selects the value of a field beyond its edges
+type Border int
+
+const (
+	// Clamp repeats the pixels at the edges of the field. This is the default.
+	Clamp Border = iota
+	// Wrap tiles the field, so that leaving one edge re-enters from the opposite edge
+	Wrap
+	// Mirror tiles the field, reflecting alternate tiles
+	Mirror
+	// Constant treats every pixel beyond the edges as having the Sampler's Constant value
+	Constant
+)
+
+// Sampler samples a field at continuous coordinates, using the same coordinate system as images
+// where the centre of the pixel at (x, y) lies at (x+0.5, y+0.5).
+// The zero value samples bilinearly and clamps to the edges of the field.
+type Sampler struct {
+	Filter Filter
+	Border Border
+	// Constant is the distance beyond the edges of the field when Border is Constant
+	Constant float64
+}
+
+// Sample returns the distance at (x, y), interpolated between pixels with the bilinear filter
+// and clamped to the edges of the field
+func (sdf *SDF) Sample(x, y float64) float64 {
+	return Sampler{}.Sample(sdf, x, y)
+}
+
+// Gradient returns the gradient of the field at (x, y), as sampled by Sample
+func (sdf *SDF) Gradient(x, y float64) (float64, float64) {
+	return Sampler{}.Gradient(sdf, x, y)
+}
+
+// Normal returns the gradient of the field at (x, y) scaled to unit length, as sampled by Sample
+func (sdf *SDF) Normal(x, y float64) (float64, float64) {
+	return Sampler{}.Normal(sdf, x, y)
+}
+
+// Sample returns the distance at (x, y), interpolated between pixels
+func (s Sampler) Sample(sdf *SDF, x, y float64) float64 {
+	v, _, _ := s.sample(sdf, x, y, false)
+	return v
+}
+
+// Gradient returns the exact derivative of the interpolated field at (x, y). In a true distance
+// field it has unit length and points away from the nearest edge.
+func (s Sampler) Gradient(sdf *SDF, x, y float64) (float64, float64) {
+	_, dx, dy := s.sample(sdf, x, y, true)
+	return dx, dy
+}
+
+// Normal returns the gradient of the field at (x, y) scaled to unit length, or (0, 0) where the
+// field is flat
+func (s Sampler) Normal(sdf *SDF, x, y float64) (float64, float64) {
+	dx, dy := s.Gradient(sdf, x, y)
+	l := math.Hypot(dx, dy)
+	if l == 0 || math.IsNaN(l) || math.IsInf(l, 0) {
+		return 0, 0
+	}
+	return dx / l, dy / l
+}
+
+func (s Sampler) sample(sdf *SDF, x, y float64, gradient bool) (float64, float64, float64) {
+	// move to coordinates where pixel centres lie on integers
+	x, y = x-0.5, y-0.5
+	x0, y0 := math.Floor(x), math.Floor(y)
+
+	var wx, wy, dwx, dwy [4]float64
+	var first int
+	switch s.Filter {
+	case Bicubic:
+		wx, dwx = catmullRom(x - x0)
+		wy, dwy = catmullRom(y - y0)
+		first = -1
+	default:
+		wx, dwx = linear(x - x0)
+		wy, dwy = linear(y - y0)
+	}
+
+	var v, dx, dy float64
+	for j := 0; j < 4; j++ {
+		if wy[j] == 0 && (!gradient || dwy[j] == 0) {
+			continue
+		}
+		py := int(y0) + first + j
+
+		for i := 0; i < 4; i++ {
+			if wx[i] == 0 && (!gradient || dwx[i] == 0) {
+				continue
+			}
+			d := s.at(sdf, int(x0)+first+i, py)
+
+			// skipping unused pixels also avoids 0*Inf for fields without a boundary
+			if w := wx[i] * wy[j]; w != 0 {
+				v += w * d
+			}
+			if gradient {
+				if w := dwx[i] * wy[j]; w != 0 {
+					dx += w * d
+				}
+				if w := wx[i] * dwy[j]; w != 0 {
+					dy += w * d
+				}
+			}
+		}
+	}
+
+	return v, dx, dy
+}
+
+// at returns the distance of a pixel which may lie beyond the edges of the field
+func (s Sampler) at(sdf *SDF, x, y int) float64 {
+	if s.Border == Constant {
+		if x < 0 || y < 0 || x >= sdf.Width || y >= sdf.Height {
+			return s.Constant
+		}
+		return sdf.At(x, y)
+	}
+	return sdf.At(s.Border.index(x, sdf.Width), s.Border.index(y, sdf.Height))
+}
+
+// index maps a pixel index which may be out of the range [0, n) back into it
+func (b Border) index(i, n int) int {
+	switch b {
+	case Wrap:
+		i %= n
+		if i < 0 {
+			i += n
+		}
+		return i
+	case Mirror:
+		i %= 2 * n
+		if i < 0 {
+			i += 2 * n
+		}
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+		return i
+	}
+	return clampInt(i, 0, n-1)
+}
+
+// linear returns the weights, and their derivatives, of the 2 pixels either side of t
+func linear(t float64) ([4]float64, [4]float64) {
+	return [4]float64{1 - t, t}, [4]float64{-1, 1}
+}
+
+// catmullRom returns the weights, and their derivatives, of the 4 pixels around t
+func catmullRom(t float64) ([4]float64, [4]float64) {
+	t2, t3 := t*t, t*t*t
+	w := [4]float64{
+		(-t3 + 2*t2 - t) / 2,
+		(3*t3 - 5*t2 + 2) / 2,
+		(-3*t3 + 4*t2 + t) / 2,
+		(t3 - t2) / 2,
+	}
+	dw := [4]float64{
+		(-3*t2 + 4*t - 1) / 2,
+		(9*t2 - 10*t) / 2,
+		(-9*t2 + 8*t + 1) / 2,
+		(3*t2 - 2*t) / 2,
+	}
+	return w, dw
+}
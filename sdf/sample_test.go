@@ -0,0 +1,117 @@
+package sdf
+
+import (
+	"math"
+	"testing"
+)
+
+var filters = []Filter{Bilinear, Bicubic}
+
+func TestSampleAtPixelCentres(t *testing.T) {
+	s := circleField(9, 7, 4.2, 3.1, 2.5)
+
+	for _, filter := range filters {
+		for _, border := range []Border{Clamp, Wrap, Mirror, Constant} {
+			sampler := Sampler{Filter: filter, Border: border, Constant: 100}
+			for y := 0; y < s.Height; y++ {
+				for x := 0; x < s.Width; x++ {
+					if v := sampler.Sample(s, float64(x)+0.5, float64(y)+0.5); math.Abs(v-s.At(x, y)) > 1e-12 {
+						t.Errorf("%+v: sample at the centre of (%v, %v) should be %v, not %v", sampler, x, y, s.At(x, y), v)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSampleLinearField(t *testing.T) {
+	// both filters reproduce a linear field, and its gradient, exactly
+	s := fieldOf(8, 8, func(x, y float64) float64 {
+		return 2*x - 3*y + 1
+	})
+
+	for _, filter := range filters {
+		sampler := Sampler{Filter: filter}
+		for y := 2.0; y < 6; y += 0.37 {
+			for x := 2.0; x < 6; x += 0.29 {
+				expected := 2*(x-0.5) - 3*(y-0.5) + 1
+				if v := sampler.Sample(s, x, y); math.Abs(v-expected) > 1e-9 {
+					t.Errorf("%+v: sample at (%v, %v) should be %v, not %v", sampler, x, y, expected, v)
+				}
+				if dx, dy := sampler.Gradient(s, x, y); math.Abs(dx-2) > 1e-9 || math.Abs(dy+3) > 1e-9 {
+					t.Errorf("%+v: gradient at (%v, %v) should be (2, -3), not (%v, %v)", sampler, x, y, dx, dy)
+				}
+			}
+		}
+	}
+}
+
+func TestSampleBilinear(t *testing.T) {
+	s := newSDF(2, 2, 0, 1, 2, 4)
+
+	if v := s.Sample(1, 1); v != 1.75 {
+		t.Errorf("Sample between the four pixels should average them as 1.75, not %v", v)
+	}
+	if dx, dy := s.Gradient(1, 1); dx != 1.5 || dy != 2.5 {
+		t.Errorf("Gradient between the four pixels should be (1.5, 2.5), not (%v, %v)", dx, dy)
+	}
+}
+
+func TestSampleBorders(t *testing.T) {
+	s := newSDF(3, 1, 1, 2, 3)
+
+	tests := []struct {
+		border   Border
+		expected []float64 // at pixels -2, -1, 3 and 4
+	}{
+		{Clamp, []float64{1, 1, 3, 3}},
+		{Wrap, []float64{2, 3, 1, 2}},
+		{Mirror, []float64{2, 1, 3, 2}},
+		{Constant, []float64{9, 9, 9, 9}},
+	}
+
+	for _, tt := range tests {
+		sampler := Sampler{Border: tt.border, Constant: 9}
+		for i, x := range []int{-2, -1, 3, 4} {
+			if v := sampler.Sample(s, float64(x)+0.5, 0.5); v != tt.expected[i] {
+				t.Errorf("Border %v: sample of pixel %v should be %v, not %v", tt.border, x, tt.expected[i], v)
+			}
+		}
+	}
+}
+
+func TestGradientOfCircle(t *testing.T) {
+	s := circleField(32, 32, 16, 16, 8)
+
+	for _, filter := range filters {
+		sampler := Sampler{Filter: filter}
+		for _, p := range [][2]float64{{5.3, 16.5}, {27.2, 20.1}, {16.5, 29.4}, {10.1, 9.7}} {
+			// the field is sampled from pixel centres at integers, hence the offset
+			nx, ny := p[0]-0.5-16, p[1]-0.5-16
+			l := math.Hypot(nx, ny)
+			nx, ny = nx/l, ny/l
+
+			dx, dy := sampler.Normal(s, p[0], p[1])
+			if math.Abs(dx-nx) > 0.05 || math.Abs(dy-ny) > 0.05 {
+				t.Errorf("%+v: normal at %v should be about (%v, %v), not (%v, %v)", sampler, p, nx, ny, dx, dy)
+			}
+			if gx, gy := sampler.Gradient(s, p[0], p[1]); math.Abs(math.Hypot(gx, gy)-1) > 0.05 {
+				t.Errorf("%+v: gradient at %v should have about unit length, not %v", sampler, p, math.Hypot(gx, gy))
+			}
+		}
+	}
+}
+
+func TestSampleWithoutBoundary(t *testing.T) {
+	s := newSDF(2, 2, math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(1))
+
+	for _, filter := range filters {
+		sampler := Sampler{Filter: filter}
+		if v := sampler.Sample(s, 0.5, 0.5); !math.IsInf(v, 1) {
+			t.Errorf("%+v: sample of a field without a boundary should be +Inf, not %v", sampler, v)
+		}
+		if dx, dy := sampler.Normal(s, 0.5, 0.5); dx != 0 || dy != 0 {
+			t.Errorf("%+v: normal of a field without a boundary should be (0, 0), not (%v, %v)", sampler, dx, dy)
+		}
+	}
+}
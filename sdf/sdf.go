@@ -16,6 +16,11 @@ type SDF struct {
 	Height int
 }
 
+// MaxPixels is the largest number of pixels in a field decoded from data, such as by
+// UnmarshalBinary or the readers of package sdfio. At 16 bytes a pixel, every byte of the largest
+// DisplacementField is addressable by an int32.
+const MaxPixels = math.MaxInt32 / 16
+
 // New returns a zeroed SDF of the given size
 func New(w, h int) *SDF {
 	return &SDF{
@@ -42,44 +47,55 @@ type DisplacementField struct {
 	boundaryPts []point
 }
 
-// NearestBoundaryAt returns X,Y coordinate of the nearest boundary point from the given point
+// NewDisplacementField returns a zeroed DisplacementField of the given size in which no pixel
+// has a nearest boundary point
+func NewDisplacementField(w, h int) *DisplacementField {
+	df := &DisplacementField{New(w, h), make([]point, w*h)}
+	for i := range df.boundaryPts {
+		df.boundaryPts[i] = noPoint
+	}
+	return df
+}
+
+// NearestBoundaryAt returns X,Y coordinate of the nearest boundary point from the given point.
+// If the field has no boundary, as for an empty stencil, then (-1, -1) is returned.
 func (df *DisplacementField) NearestBoundaryAt(x, y int) (int, int) {
 	pt := df.boundaryPts[y*df.Width+x]
 	return pt.x, pt.y
 }
 
-// Calculate a new DisplacementField from the given Stencil
-func Calculate(s Stencil) *DisplacementField {
-	w, h := s.Size()
-	df := DisplacementField{
-		New(w, h),
-		make([]point, w*h),
-	}
-
-	pts := findBoundaries(s)
-
-	for y := 0; y < h; y++ {
-		for x := 0; x < w; x++ {
-			pt, dst := point{x, y}.nearest(pts)
-
-			// use -ve sign if we are inside and +ve if outside
-			if s.Within(x, y) {
-				dst = -dst
-			}
-
-			df.Set(x, y, dst)
-			df.boundaryPts[y*w+x] = *pt
-		}
-	}
+// LookupNearestBoundary returns X,Y coordinate of the nearest boundary point from the given point.
+// The boolean reports whether there is a boundary point at all, which there is not for an empty stencil.
+func (df *DisplacementField) LookupNearestBoundary(x, y int) (int, int, bool) {
+	pt := df.boundaryPts[y*df.Width+x]
+	return pt.x, pt.y, pt != noPoint
+}
 
-	return &df
+// SetNearestBoundary sets the X,Y coordinate of the nearest boundary point from the given point.
+// Setting it to (-1, -1) marks the point as having no boundary.
+func (df *DisplacementField) SetNearestBoundary(x, y, bx, by int) {
+	df.boundaryPts[y*df.Width+x] = point{bx, by}
 }
 
-func findBoundaries(s Stencil) []point {
-	boundaryPts := []point{}
+// Calculate a new DisplacementField from the given Stencil using the default Options, except
+// that the work is done by a single worker so the Stencil is never read concurrently. Use
+// CalculateWithOptions to spread the work across more workers.
+//
+// Pixels with no boundary to measure to, as for an empty stencil, are infinitely far away so
+// their field value is +Inf if outside the stencil or -Inf if inside. Use CalculateWithOptions
+// to instead limit distances with Options.MaxDistance or to reject such stencils with an error.
+func Calculate(s Stencil) *DisplacementField {
+	// the default options are always valid so there is no error to handle
+	df, _ := CalculateWithOptions(s, Options{Workers: 1})
+	return df
+}
 
+// findBoundaries returns a mask flagging which pixels lie on the boundary of the stencil
+func findBoundaries(s Stencil, r *runner) ([]bool, error) {
 	w, h := s.Size()
-	for y := 0; y < h; y++ {
+	boundary := make([]bool, w*h)
+
+	err := r.each(h, func(y int) {
 		for x := 0; x < w; x++ {
 			// boundaries are any points within the stencil that have adjacent points outside the stencil
 			if s.Within(x, y) {
@@ -88,17 +104,19 @@ func findBoundaries(s Stencil) []point {
 				topTransparent := y == 0 || !s.Within(x, y-1)
 				botTransparent := y == h-1 || !s.Within(x, y+1)
 
-				if lftTransparent || rgtTransparent || topTransparent || botTransparent {
-					boundaryPts = append(boundaryPts, point{x, y})
-				}
+				boundary[y*w+x] = lftTransparent || rgtTransparent || topTransparent || botTransparent
 			}
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return boundaryPts
+	return boundary, nil
 }
 
-// Draw returns an 8-bit grayscale representation of a Signed-Distance-Field
+// Draw returns an 8-bit grayscale representation of a Signed-Distance-Field, mapping
+// distances in range [-127, 128] to [0, 255]. Use DrawEncoded to choose the mapping.
 func (sdf *SDF) Draw() *image.Gray {
 	gray := image.NewGray(image.Rect(0, 0, sdf.Width, sdf.Height))
 
@@ -119,6 +137,13 @@ func (sdf *SDF) Draw() *image.Gray {
 
 // Lerp returns the linear interpolation between two SDFs, weighted by t in range [0, 1]
 func Lerp(a *SDF, b *SDF, t float64) (*SDF, error) {
+	return combine(a, b, func(da, db float64) float64 {
+		return da + (db-da)*t
+	})
+}
+
+// combine returns a new SDF holding f applied to each pair of corresponding distances
+func combine(a *SDF, b *SDF, f func(da, db float64) float64) (*SDF, error) {
 	if a.Width != b.Width {
 		return nil, errors.New("SDF a and SDF b must have matching width")
 	}
@@ -128,7 +153,7 @@ func Lerp(a *SDF, b *SDF, t float64) (*SDF, error) {
 
 	ret := New(a.Width, a.Height)
 	for i := range a.Field {
-		ret.Field[i] = a.Field[i] + (b.Field[i]-a.Field[i])*t
+		ret.Field[i] = f(a.Field[i], b.Field[i])
 	}
 
 	return ret, nil
@@ -1,6 +1,9 @@
 package sdf
 
 import (
+	"math"
+	"runtime"
+	"sync/atomic"
 	"testing"
 )
 
@@ -106,6 +109,33 @@ func TestCalculate(t *testing.T) {
 	}
 }
 
+// serialStencil is a circle that records whether it was ever read concurrently
+type serialStencil struct {
+	reading    *int32
+	concurrent *int32
+}
+
+func (s serialStencil) Within(x, y int) bool {
+	if atomic.AddInt32(s.reading, 1) > 1 {
+		atomic.StoreInt32(s.concurrent, 1)
+	}
+	runtime.Gosched()
+	atomic.AddInt32(s.reading, -1)
+	return math.Hypot(float64(x)-16, float64(y)-16) < 8
+}
+
+func (s serialStencil) Size() (int, int) {
+	return 32, 32
+}
+
+func TestCalculateIsSerial(t *testing.T) {
+	var reading, concurrent int32
+	Calculate(serialStencil{&reading, &concurrent})
+	if concurrent != 0 {
+		t.Errorf("Calculate should not read the stencil concurrently")
+	}
+}
+
 func TestDraw(t *testing.T) {
 	sdf := New(3, 5)
 
@@ -205,3 +235,64 @@ func TestLerpWithMismatchedSDFs(t *testing.T) {
 		t.Errorf("Lerp should return an erro when SDFs are mismatched sizes")
 	}
 }
+
+func TestLookupNearestBoundary(t *testing.T) {
+	df := DisplacementField{
+		New(2, 1),
+		[]point{
+			point{10, 50},
+			noPoint,
+		},
+	}
+
+	nx, ny, ok := df.LookupNearestBoundary(0, 0)
+	if !ok || nx != 10 || ny != 50 {
+		t.Errorf("Nearest point should be (10, 50, true), not (%v, %v, %v)", nx, ny, ok)
+	}
+
+	nx, ny, ok = df.LookupNearestBoundary(1, 0)
+	if ok {
+		t.Errorf("Nearest point should not be found where there is no boundary, not (%v, %v)", nx, ny)
+	}
+}
+
+func TestCalculateEmptyStencil(t *testing.T) {
+	df := Calculate(maskStencil{3, 2, make([]bool, 3*2)})
+
+	for y := 0; y < df.Height; y++ {
+		for x := 0; x < df.Width; x++ {
+			if f := df.At(x, y); !math.IsInf(f, 1) {
+				t.Errorf("Field value at (%v, %v) of an empty stencil should be +Inf, not %v", x, y, f)
+			}
+
+			if nx, ny := df.NearestBoundaryAt(x, y); nx != -1 || ny != -1 {
+				t.Errorf("Nearest boundary at (%v, %v) of an empty stencil should be (-1, -1), not (%v, %v)", x, y, nx, ny)
+			}
+
+			if _, _, ok := df.LookupNearestBoundary(x, y); ok {
+				t.Errorf("Nearest boundary at (%v, %v) of an empty stencil should not be found", x, y)
+			}
+		}
+	}
+}
+
+func TestNewDisplacementField(t *testing.T) {
+	df := NewDisplacementField(3, 2)
+
+	if df.Width != 3 || df.Height != 2 || len(df.Field) != 6 {
+		t.Fatalf("DisplacementField should be 3x2")
+	}
+	if _, _, ok := df.LookupNearestBoundary(2, 1); ok {
+		t.Errorf("New DisplacementField should have no boundary")
+	}
+
+	df.SetNearestBoundary(2, 1, 0, 1)
+	if x, y, ok := df.LookupNearestBoundary(2, 1); x != 0 || y != 1 || !ok {
+		t.Errorf("Nearest boundary should be set to (0, 1), not (%v, %v)", x, y)
+	}
+
+	df.SetNearestBoundary(2, 1, -1, -1)
+	if _, _, ok := df.LookupNearestBoundary(2, 1); ok {
+		t.Errorf("Setting the nearest boundary to (-1, -1) should mark it as having none")
+	}
+}
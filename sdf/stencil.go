@@ -14,6 +14,14 @@ type Stencil interface {
 	Size() (int, int)
 }
 
+// CoverageStencil is a Stencil that also knows how much of each pixel is covered by the surface.
+// This allows the AntiAliased algorithm to locate edges with sub-pixel accuracy.
+type CoverageStencil interface {
+	Stencil
+	// Coverage returns the fraction, in range [0, 1], of the given pixel covered by the surface
+	Coverage(x, y int) float64
+}
+
 const (
 	// OpaqueAlpha is an alpha-threshold so only fully-opaque pixels will be within the stencil
 	OpaqueAlpha = uint16(math.MaxUint16)
@@ -22,7 +30,7 @@ const (
 )
 
 // ImageAlphaStencil implements a Stencil where the alpha channel of an image is thresholded
-// against the Alpha value
+// against the Alpha value. It is also a CoverageStencil, using the alpha channel as coverage.
 type ImageAlphaStencil struct {
 	Image image.Image
 	Alpha uint16
@@ -35,6 +43,13 @@ func (s ImageAlphaStencil) Within(x, y int) bool {
 	return a >= uint32(s.Alpha)
 }
 
+// Coverage returns the alpha of the given pixel as the fraction of the pixel covered by the surface
+func (s ImageAlphaStencil) Coverage(x, y int) float64 {
+	b := s.Image.Bounds()
+	_, _, _, a := s.Image.At(b.Min.X+x, b.Min.Y+y).RGBA()
+	return float64(a) / math.MaxUint16
+}
+
 // Size returns the width and height of the ImageAlphaStencil
 func (s ImageAlphaStencil) Size() (int, int) {
 	size := s.Image.Bounds().Size()
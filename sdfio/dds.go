@@ -0,0 +1,94 @@
+package sdfio
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// DXGI formats of each TextureFormat
+var ddsFormats = map[TextureFormat]uint32{
+	R8:   61, // DXGI_FORMAT_R8_UNORM
+	R16F: 54, // DXGI_FORMAT_R16_FLOAT
+	R32F: 41, // DXGI_FORMAT_R32_FLOAT
+	BC4:  80, // DXGI_FORMAT_BC4_UNORM
+}
+
+// DDS header flags
+const (
+	ddsCaps        = 0x1
+	ddsHeight      = 0x2
+	ddsWidth       = 0x4
+	ddsPitch       = 0x8
+	ddsPixelFormat = 0x1000
+	ddsMipMapCount = 0x20000
+	ddsLinearSize  = 0x80000
+
+	ddsFourCC = 0x4
+
+	ddsCapsComplex = 0x8
+	ddsCapsTexture = 0x1000
+	ddsCapsMipMap  = 0x400000
+)
+
+// WriteDDS writes a field as a single channel DirectDraw Surface texture, with a mip chain
+// generated by repeatedly halving the field. The format is given by a DX10 extended header.
+func WriteDDS(w io.Writer, f *sdf.SDF, o TextureOptions) error {
+	o, err := o.validate(f)
+	if err != nil {
+		return err
+	}
+	levels := o.encodeLevels(f)
+
+	flags := uint32(ddsCaps | ddsHeight | ddsWidth | ddsPixelFormat | ddsMipMapCount)
+	caps := uint32(ddsCapsTexture)
+	if len(levels) > 1 {
+		caps |= ddsCapsComplex | ddsCapsMipMap
+	}
+
+	// compressed textures give the size of the largest level, others the bytes per row
+	pitch := uint32(f.Width * o.blockSize())
+	if o.Format == BC4 {
+		flags |= ddsLinearSize
+		pitch = uint32(len(levels[0].data))
+	} else {
+		flags |= ddsPitch
+	}
+
+	header := []byte("DDS ")
+	header = appendUint32(header, 124)
+	header = appendUint32(header, flags)
+	header = appendUint32(header, uint32(f.Height))
+	header = appendUint32(header, uint32(f.Width))
+	header = appendUint32(header, pitch)
+	header = appendUint32(header, 0) // depth
+	header = appendUint32(header, uint32(len(levels)))
+	header = append(header, make([]byte, 4*11)...)
+
+	// the pixel format defers to the DX10 header
+	header = appendUint32(header, 32)
+	header = appendUint32(header, ddsFourCC)
+	header = append(header, "DX10"...)
+	header = append(header, make([]byte, 4*5)...)
+
+	header = appendUint32(header, caps)
+	header = append(header, make([]byte, 4*4)...)
+
+	header = appendUint32(header, ddsFormats[o.Format])
+	header = appendUint32(header, 3) // 2D texture
+	header = appendUint32(header, 0)
+	header = appendUint32(header, 1) // array size
+	header = appendUint32(header, 0)
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+	for _, level := range levels {
+		if _, err := bw.Write(level.data); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
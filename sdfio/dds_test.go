@@ -0,0 +1,63 @@
+package sdfio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+func TestWriteDDS(t *testing.T) {
+	for _, test := range []struct {
+		format     TextureFormat
+		dxgiFormat uint32
+		pitch      uint32
+		size       int
+	}{
+		{R8, 61, 8, 8*8 + 4*4 + 2*2 + 1},
+		{R16F, 54, 16, 2 * (8*8 + 4*4 + 2*2 + 1)},
+		{R32F, 41, 32, 4 * (8*8 + 4*4 + 2*2 + 1)},
+		{BC4, 80, 32, 8 * (4 + 1 + 1 + 1)},
+	} {
+		var buf bytes.Buffer
+		if err := WriteDDS(&buf, sdf.New(8, 8), TextureOptions{Format: test.format}); err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		data := buf.Bytes()
+
+		if !bytes.HasPrefix(data, []byte("DDS ")) || binary.LittleEndian.Uint32(data[4:]) != 124 {
+			t.Fatalf("File should start with the magic number and a 124 byte header")
+		}
+		if string(data[84:88]) != "DX10" {
+			t.Errorf("Pixel format should defer to the DX10 header, not %q", data[84:88])
+		}
+
+		read := func(offset int) uint32 { return binary.LittleEndian.Uint32(data[offset:]) }
+		if h, w := read(12), read(16); w != 8 || h != 8 {
+			t.Errorf("Size should be 8x8, not %vx%v", w, h)
+		}
+		if pitch := read(20); pitch != test.pitch {
+			t.Errorf("Pitch of format %v should be %v, not %v", test.format, test.pitch, pitch)
+		}
+		if mips := read(28); mips != 4 {
+			t.Errorf("Mip count should be 4, not %v", mips)
+		}
+		if format := read(128); format != test.dxgiFormat {
+			t.Errorf("DXGI format should be %v, not %v", test.dxgiFormat, format)
+		}
+		if len(data) != 148+test.size {
+			t.Errorf("File of format %v should be %v bytes, not %v", test.format, 148+test.size, len(data))
+		}
+	}
+}
+
+func TestWriteDDSErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDDS(&buf, sdf.New(0, 0), TextureOptions{}); err == nil {
+		t.Errorf("Writing an empty field should return an error")
+	}
+	if err := WriteDDS(&buf, sdf.New(4, 4), TextureOptions{Levels: 4}); err == nil {
+		t.Errorf("Writing more levels than a full mip chain should return an error")
+	}
+}
@@ -0,0 +1,499 @@
+package sdfio
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// EXRCompression selects how the pixels of an OpenEXR file are compressed
+type EXRCompression int
+
+const (
+	// EXRNone stores pixels uncompressed, one scanline per block
+	EXRNone EXRCompression = iota
+	// EXRZip compresses blocks of 16 scanlines with zlib, as OpenEXR's ZIP_COMPRESSION
+	EXRZip
+)
+
+// Channel is one named channel of a multi-channel field, such as the red channel of an MSDF
+type Channel struct {
+	Name  string
+	Field *sdf.SDF
+}
+
+const exrMagic = "\x76\x2f\x31\x01"
+
+// OpenEXR pixel types
+const (
+	exrUint  = 0
+	exrHalf  = 1
+	exrFloat = 2
+)
+
+// OpenEXR compression methods
+const (
+	exrNoCompression   = 0
+	exrZipsCompression = 2
+	exrZipCompression  = 3
+)
+
+// WriteEXR writes a field as a single channel OpenEXR image, with the channel named Y so that
+// viewers show it as gray. Distances are stored as Half or Float32 precision.
+func WriteEXR(w io.Writer, f *sdf.SDF, p Precision, c EXRCompression) error {
+	return WriteEXRChannels(w, []Channel{{"Y", f}}, p, c)
+}
+
+// WriteEXRChannels writes fields of the same size as the channels of a scanline OpenEXR image.
+// Channels are stored sorted by name, as the format requires. Distances are stored as Half or
+// Float32 precision.
+func WriteEXRChannels(w io.Writer, channels []Channel, p Precision, c EXRCompression) error {
+	if len(channels) == 0 {
+		return errors.New("exr image must have at least 1 channel")
+	}
+
+	channels = append([]Channel(nil), channels...)
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+
+	width, height := channels[0].Field.Width, channels[0].Field.Height
+	for i, ch := range channels {
+		if ch.Name == "" || len(ch.Name) > 31 {
+			return fmt.Errorf("exr channel name %q must be 1 to 31 bytes long", ch.Name)
+		}
+		if i > 0 && ch.Name == channels[i-1].Name {
+			return fmt.Errorf("exr channel name %q is repeated", ch.Name)
+		}
+		if ch.Field.Width != width || ch.Field.Height != height {
+			return errors.New("exr channels must have matching width and height")
+		}
+	}
+	if width <= 0 || height <= 0 {
+		return errors.New("field dimensions must be positive")
+	}
+
+	var pixelType int32
+	var size int
+	switch p {
+	case Half:
+		pixelType, size = exrHalf, 2
+	case Float32:
+		pixelType, size = exrFloat, 4
+	default:
+		return errors.New("exr images can only store Half or Float32 precision")
+	}
+
+	var compression byte
+	linesPerBlock := 1
+	switch c {
+	case EXRNone:
+		compression = exrNoCompression
+	case EXRZip:
+		compression, linesPerBlock = exrZipCompression, 16
+	default:
+		return errors.New("unknown exr compression")
+	}
+
+	// chunks are built up front because their offsets precede them in the file
+	var chunks [][]byte
+	for y0 := 0; y0 < height; y0 += linesPerBlock {
+		y1 := y0 + linesPerBlock
+		if y1 > height {
+			y1 = height
+		}
+
+		data := make([]byte, 0, (y1-y0)*len(channels)*width*size)
+		for y := y0; y < y1; y++ {
+			for _, ch := range channels {
+				for x := 0; x < width; x++ {
+					v := ch.Field.At(x, y)
+					if p == Half {
+						data = appendUint16(data, toHalf(v))
+					} else {
+						data = appendUint32(data, math.Float32bits(float32(v)))
+					}
+				}
+			}
+		}
+
+		if c == EXRZip {
+			compressed, err := zipCompress(data)
+			if err != nil {
+				return err
+			}
+			// blocks that do not shrink are stored uncompressed, as OpenEXR expects
+			if len(compressed) < len(data) {
+				data = compressed
+			}
+		}
+
+		chunk := appendUint32(nil, uint32(y0))
+		chunk = appendUint32(chunk, uint32(len(data)))
+		chunks = append(chunks, append(chunk, data...))
+	}
+
+	var chlist []byte
+	for _, ch := range channels {
+		chlist = append(chlist, ch.Name...)
+		chlist = append(chlist, 0)
+		chlist = appendUint32(chlist, uint32(pixelType))
+		chlist = append(chlist, 0, 0, 0, 0) // pLinear and reserved
+		chlist = appendUint32(chlist, 1)    // x sampling
+		chlist = appendUint32(chlist, 1)    // y sampling
+	}
+	chlist = append(chlist, 0)
+
+	window := appendUint32(appendUint32(nil, 0), 0)
+	window = appendUint32(appendUint32(window, uint32(width-1)), uint32(height-1))
+
+	var header bytes.Buffer
+	header.WriteString(exrMagic)
+	header.Write([]byte{2, 0, 0, 0})
+	writeEXRAttribute(&header, "channels", "chlist", chlist)
+	writeEXRAttribute(&header, "compression", "compression", []byte{compression})
+	writeEXRAttribute(&header, "dataWindow", "box2i", window)
+	writeEXRAttribute(&header, "displayWindow", "box2i", window)
+	writeEXRAttribute(&header, "lineOrder", "lineOrder", []byte{0})
+	writeEXRAttribute(&header, "pixelAspectRatio", "float", appendUint32(nil, math.Float32bits(1)))
+	writeEXRAttribute(&header, "screenWindowCenter", "v2f", make([]byte, 8))
+	writeEXRAttribute(&header, "screenWindowWidth", "float", appendUint32(nil, math.Float32bits(1)))
+	header.WriteByte(0)
+
+	offset := uint64(header.Len() + 8*len(chunks))
+	for _, chunk := range chunks {
+		binary.Write(&header, binary.LittleEndian, offset)
+		offset += uint64(len(chunk))
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(header.Bytes()); err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if _, err := bw.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func writeEXRAttribute(buf *bytes.Buffer, name, typ string, value []byte) {
+	buf.WriteString(name)
+	buf.WriteByte(0)
+	buf.WriteString(typ)
+	buf.WriteByte(0)
+	binary.Write(buf, binary.LittleEndian, uint32(len(value)))
+	buf.Write(value)
+}
+
+// ReadEXR reads a field from a single channel scanline OpenEXR image
+func ReadEXR(r io.Reader) (*sdf.SDF, error) {
+	channels, err := ReadEXRChannels(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) != 1 {
+		return nil, fmt.Errorf("exr image should have 1 channel, not %v", len(channels))
+	}
+	return channels[0].Field, nil
+}
+
+// ReadEXRChannels reads every channel of a scanline OpenEXR image, in the order they are stored.
+// Channels may hold half, float or uint pixels and be uncompressed or ZIP compressed.
+func ReadEXRChannels(r io.Reader) ([]Channel, error) {
+	br := bufio.NewReader(r)
+
+	prefix := make([]byte, 8)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return nil, err
+	}
+	if string(prefix[:4]) != exrMagic {
+		return nil, errors.New("not an exr file")
+	}
+	if prefix[4] != 2 {
+		return nil, fmt.Errorf("exr version %v is not supported", prefix[4])
+	}
+	// the flags mark tiled, deep and multi-part files, leaving only long names as supported
+	if prefix[5]&^0x04 != 0 || prefix[6] != 0 || prefix[7] != 0 {
+		return nil, errors.New("only single-part scanline exr files are supported")
+	}
+
+	var (
+		channels    []exrChannel
+		compression = -1
+		window      []byte
+	)
+	for {
+		name, err := readCString(br)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			break
+		}
+		typ, err := readCString(br)
+		if err != nil {
+			return nil, err
+		}
+		var size uint32
+		if err := binary.Read(br, binary.LittleEndian, &size); err != nil {
+			return nil, err
+		}
+		if size > 1<<20 {
+			return nil, fmt.Errorf("exr attribute %q is too large", name)
+		}
+		value := make([]byte, size)
+		if _, err := io.ReadFull(br, value); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case name == "channels" && typ == "chlist":
+			if channels, err = parseChannels(value); err != nil {
+				return nil, err
+			}
+		case name == "compression" && typ == "compression" && size == 1:
+			compression = int(value[0])
+		case name == "dataWindow" && typ == "box2i" && size == 16:
+			window = value
+		}
+	}
+	if len(channels) == 0 || compression < 0 || window == nil {
+		return nil, errors.New("exr header should have channels, compression and dataWindow attributes")
+	}
+
+	xMin := int(int32(binary.LittleEndian.Uint32(window)))
+	yMin := int(int32(binary.LittleEndian.Uint32(window[4:])))
+	width := int(int32(binary.LittleEndian.Uint32(window[8:]))) - xMin + 1
+	height := int(int32(binary.LittleEndian.Uint32(window[12:]))) - yMin + 1
+	if err := checkSize(width, height); err != nil {
+		return nil, err
+	}
+	if width*height > sdf.MaxPixels/len(channels) {
+		return nil, errors.New("field is too large")
+	}
+
+	linesPerBlock := 1
+	switch compression {
+	case exrNoCompression, exrZipsCompression:
+	case exrZipCompression:
+		linesPerBlock = 16
+	default:
+		return nil, fmt.Errorf("exr compression %v is not supported", compression)
+	}
+
+	lineSize := 0
+	for _, ch := range channels {
+		lineSize += width * ch.size()
+	}
+
+	// chunks are read in the order they are stored so the offset table is not needed
+	numChunks := (height + linesPerBlock - 1) / linesPerBlock
+	if _, err := br.Discard(8 * numChunks); err != nil {
+		return nil, err
+	}
+
+	// the fields are only allocated once every block has been read, so that memory grows with
+	// the data actually present rather than the size claimed by the header
+	type block struct {
+		y0, lines int
+		data      []byte
+	}
+	blocks := make([]block, 0, numChunks)
+	for i := 0; i < numChunks; i++ {
+		var chunk struct{ Y, Size int32 }
+		if err := binary.Read(br, binary.LittleEndian, &chunk); err != nil {
+			return nil, err
+		}
+		y0 := int(chunk.Y) - yMin
+		if y0 < 0 || y0 >= height || y0%linesPerBlock != 0 {
+			return nil, fmt.Errorf("exr block at line %v lies outside the image", chunk.Y)
+		}
+		lines := linesPerBlock
+		if y0+lines > height {
+			lines = height - y0
+		}
+
+		expected := lines * lineSize
+		if chunk.Size <= 0 || int(chunk.Size) > expected {
+			return nil, fmt.Errorf("exr block at line %v has an invalid size", chunk.Y)
+		}
+		data, err := readFull(br, int(chunk.Size))
+		if err != nil {
+			return nil, err
+		}
+		if compression != exrNoCompression && len(data) < expected {
+			var err error
+			if data, err = zipDecompress(data, expected); err != nil {
+				return nil, err
+			}
+		}
+		if len(data) != expected {
+			return nil, fmt.Errorf("exr block at line %v has an invalid size", chunk.Y)
+		}
+
+		blocks = append(blocks, block{y0, lines, data})
+	}
+
+	fields := make([]*sdf.SDF, len(channels))
+	for i := range fields {
+		fields[i] = sdf.New(width, height)
+	}
+	for _, b := range blocks {
+		data := b.data
+		for y := b.y0; y < b.y0+b.lines; y++ {
+			for c, ch := range channels {
+				for x := 0; x < width; x++ {
+					fields[c].Set(x, y, ch.value(data))
+					data = data[ch.size():]
+				}
+			}
+		}
+	}
+
+	result := make([]Channel, len(channels))
+	for i, ch := range channels {
+		result[i] = Channel{ch.name, fields[i]}
+	}
+	return result, nil
+}
+
+type exrChannel struct {
+	name      string
+	pixelType int32
+}
+
+func parseChannels(data []byte) ([]exrChannel, error) {
+	var channels []exrChannel
+	for len(data) > 0 && data[0] != 0 {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 || len(data) < end+17 {
+			return nil, errors.New("invalid exr channel list")
+		}
+		ch := exrChannel{
+			name:      string(data[:end]),
+			pixelType: int32(binary.LittleEndian.Uint32(data[end+1:])),
+		}
+		xSampling := binary.LittleEndian.Uint32(data[end+9:])
+		ySampling := binary.LittleEndian.Uint32(data[end+13:])
+		if ch.pixelType < exrUint || ch.pixelType > exrFloat {
+			return nil, fmt.Errorf("exr channel %q has unknown pixel type %v", ch.name, ch.pixelType)
+		}
+		if xSampling != 1 || ySampling != 1 {
+			return nil, fmt.Errorf("exr channel %q is subsampled, which is not supported", ch.name)
+		}
+		channels = append(channels, ch)
+		data = data[end+17:]
+	}
+	return channels, nil
+}
+
+// size returns the bytes per pixel of the channel
+func (ch exrChannel) size() int {
+	if ch.pixelType == exrHalf {
+		return 2
+	}
+	return 4
+}
+
+// value decodes the pixel at the start of data
+func (ch exrChannel) value(data []byte) float64 {
+	switch ch.pixelType {
+	case exrHalf:
+		return fromHalf(binary.LittleEndian.Uint16(data))
+	case exrFloat:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+	}
+	return float64(binary.LittleEndian.Uint32(data))
+}
+
+func readCString(r *bufio.Reader) (string, error) {
+	var b []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if c == 0 {
+			return string(b), nil
+		}
+		if len(b) == 255 {
+			return "", errors.New("exr header name is too long")
+		}
+		b = append(b, c)
+	}
+}
+
+// zipCompress splits the bytes of data into its even and odd bytes, stores the differences
+// between consecutive bytes and then deflates the result, as OpenEXR's ZIP compression does
+func zipCompress(data []byte) ([]byte, error) {
+	t := make([]byte, len(data))
+	half := (len(data) + 1) / 2
+	for i, b := range data {
+		if i%2 == 0 {
+			t[i/2] = b
+		} else {
+			t[half+i/2] = b
+		}
+	}
+	for i := len(t) - 1; i > 0; i-- {
+		t[i] = t[i] - t[i-1] + 128
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(t); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// zipDecompress reverses zipCompress, expecting exactly n bytes of data
+func zipDecompress(data []byte, n int) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	t, err := readFull(zr, n)
+	if err != nil {
+		return nil, err
+	}
+	if extra, _ := zr.Read(make([]byte, 1)); extra != 0 {
+		return nil, errors.New("exr block decompresses to too many bytes")
+	}
+
+	for i := 1; i < len(t); i++ {
+		t[i] = t[i-1] + t[i] - 128
+	}
+
+	out := make([]byte, n)
+	half := (n + 1) / 2
+	for i := range out {
+		if i%2 == 0 {
+			out[i] = t[i/2]
+		} else {
+			out[i] = t[half+i/2]
+		}
+	}
+	return out, nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
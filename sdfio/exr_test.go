@@ -0,0 +1,168 @@
+package sdfio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// exrField returns a field tall enough to span several ZIP blocks
+func exrField(offset float64) *sdf.SDF {
+	f := sdf.New(7, 37)
+	for i := range f.Field {
+		f.Field[i] = float64(i%23)*0.25 - 3 + offset
+	}
+	f.Field[5] = math.Inf(1)
+	f.Field[6] = math.Inf(-1)
+	return f
+}
+
+func TestEXRRoundTrip(t *testing.T) {
+	for _, p := range []Precision{Half, Float32} {
+		for _, c := range []EXRCompression{EXRNone, EXRZip} {
+			f := exrField(0)
+
+			var buf bytes.Buffer
+			if err := WriteEXR(&buf, f, p, c); err != nil {
+				t.Fatalf("Error should have been nil, not %v", err)
+			}
+
+			read, err := ReadEXR(&buf)
+			if err != nil {
+				t.Fatalf("Error should have been nil, not %v", err)
+			}
+
+			// quarters of small distances are exact in both precisions
+			assertFieldsEqual(t, f, read)
+		}
+	}
+}
+
+func TestEXRZipIsSmaller(t *testing.T) {
+	var uncompressed, compressed bytes.Buffer
+	if err := WriteEXR(&uncompressed, exrField(0), Float32, EXRNone); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if err := WriteEXR(&compressed, exrField(0), Float32, EXRZip); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if compressed.Len() >= uncompressed.Len() {
+		t.Errorf("ZIP compressed file should be smaller than %v bytes, not %v", uncompressed.Len(), compressed.Len())
+	}
+}
+
+func TestEXRHalfPrecision(t *testing.T) {
+	f := sdf.New(2, 1)
+	f.Field[0], f.Field[1] = math.Pi, 100000
+
+	var buf bytes.Buffer
+	if err := WriteEXR(&buf, f, Half, EXRNone); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	read, err := ReadEXR(&buf)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	if v := read.Field[0]; math.Abs(v-math.Pi) > 0.002 {
+		t.Errorf("Pi should be read back as about %v, not %v", math.Pi, v)
+	}
+	if v := read.Field[1]; !math.IsInf(v, 1) {
+		t.Errorf("Distance beyond the range of a half should be read back as +Inf, not %v", v)
+	}
+}
+
+func TestEXRChannelsRoundTrip(t *testing.T) {
+	channels := []Channel{{"R", exrField(1)}, {"G", exrField(2)}, {"B", exrField(3)}}
+
+	var buf bytes.Buffer
+	if err := WriteEXRChannels(&buf, channels, Float32, EXRZip); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	read, err := ReadEXRChannels(&buf)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if len(read) != 3 {
+		t.Fatalf("There should be 3 channels, not %v", len(read))
+	}
+
+	// channels are stored sorted by name
+	for i, expected := range []Channel{channels[2], channels[1], channels[0]} {
+		if read[i].Name != expected.Name {
+			t.Errorf("Channel %v should be named %q, not %q", i, expected.Name, read[i].Name)
+		}
+		assertFieldsEqual(t, expected.Field, read[i].Field)
+	}
+
+	if _, err := ReadEXR(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Errorf("Reading 3 channels as a single field should return an error")
+	}
+}
+
+func TestWriteEXRHeader(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEXR(&buf, sdf.New(3, 2), Float32, EXRNone); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte{0x76, 0x2f, 0x31, 0x01, 2, 0, 0, 0}) {
+		t.Fatalf("File should start with the magic number and version 2, not %x", data[:8])
+	}
+	for _, attr := range []string{"channels\x00chlist\x00", "compression\x00compression\x00", "dataWindow\x00box2i\x00",
+		"displayWindow\x00box2i\x00", "lineOrder\x00lineOrder\x00", "pixelAspectRatio\x00float\x00",
+		"screenWindowCenter\x00v2f\x00", "screenWindowWidth\x00float\x00"} {
+		if !bytes.Contains(data, []byte(attr)) {
+			t.Errorf("Header should contain the required attribute %q", attr)
+		}
+	}
+
+	// 2 scanlines each of 3 float32s, preceded by their line number and size
+	chunks := 2 * (8 + 3*4)
+	offset := int(binary.LittleEndian.Uint64(data[len(data)-chunks-16:]))
+	if offset != len(data)-chunks {
+		t.Errorf("First offset should be %v, not %v", len(data)-chunks, offset)
+	}
+}
+
+func TestWriteEXRErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEXR(&buf, exrField(0), Float64, EXRNone); err == nil {
+		t.Errorf("Writing Float64 precision should return an error")
+	}
+	if err := WriteEXRChannels(&buf, nil, Float32, EXRNone); err == nil {
+		t.Errorf("Writing no channels should return an error")
+	}
+	if err := WriteEXRChannels(&buf, []Channel{{"R", exrField(0)}, {"G", sdf.New(2, 2)}}, Float32, EXRNone); err == nil {
+		t.Errorf("Writing channels of different sizes should return an error")
+	}
+	if err := WriteEXRChannels(&buf, []Channel{{"R", exrField(0)}, {"R", exrField(0)}}, Float32, EXRNone); err == nil {
+		t.Errorf("Writing repeated channel names should return an error")
+	}
+}
+
+func TestReadEXRErrors(t *testing.T) {
+	var valid bytes.Buffer
+	if err := WriteEXR(&valid, exrField(0), Half, EXRZip); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	tiled := append([]byte(nil), valid.Bytes()...)
+	tiled[5] = 0x02
+
+	for _, data := range [][]byte{
+		{},
+		[]byte("\x76\x2f\x31\x02\x02\x00\x00\x00"),
+		tiled,
+		valid.Bytes()[:valid.Len()-10],
+	} {
+		if _, err := ReadEXR(bytes.NewReader(data)); err == nil {
+			t.Errorf("Reading %q should return an error", data)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package sdfio
+
+import "math"
+
+// toHalf converts a float to the nearest IEEE 754 half-precision float, rounding ties to even.
+// Values beyond the range of a half become infinite.
+func toHalf(v float64) uint16 {
+	bits := math.Float64bits(v)
+	sign := uint16(bits>>48) & 0x8000
+	exp := int(bits>>52) & 0x7ff
+	mant := bits & (1<<52 - 1)
+
+	if exp == 0x7ff {
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+
+	e := exp - 1023 + 15
+	switch {
+	case e >= 31:
+		return sign | 0x7c00
+	case e <= 0:
+		// subnormal halves count in steps of 2^-24, and anything below half a step is zero
+		if e < -10 {
+			return sign
+		}
+		return sign | uint16(roundShift(mant|1<<52, uint(43-e)))
+	}
+
+	// rounding up may carry into the exponent, which is still correct and may reach infinity
+	h := uint64(e)<<10 + roundShift(mant, 42)
+	if h > 0x7c00 {
+		h = 0x7c00
+	}
+	return sign | uint16(h)
+}
+
+// roundShift returns m shifted right by s bits, rounding ties to even
+func roundShift(m uint64, s uint) uint64 {
+	q := m >> s
+	r := m & (1<<s - 1)
+	half := uint64(1) << (s - 1)
+	if r > half || (r == half && q&1 == 1) {
+		q++
+	}
+	return q
+}
+
+// fromHalf converts an IEEE 754 half-precision float to a float
+func fromHalf(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant != 0 {
+			return math.NaN()
+		}
+		v = math.Inf(1)
+	default:
+		v = math.Ldexp(1024+mant, exp-25)
+	}
+
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
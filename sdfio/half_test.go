@@ -0,0 +1,50 @@
+package sdfio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToHalf(t *testing.T) {
+	for _, test := range []struct {
+		v    float64
+		half uint16
+	}{
+		{0, 0x0000},
+		{math.Copysign(0, -1), 0x8000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.5, 0x3800},
+		{65504, 0x7bff},
+		{65520, 0x7c00},
+		{math.Inf(-1), 0xfc00},
+		{math.Ldexp(1, -24), 0x0001},
+		{math.Ldexp(1, -25), 0x0000},
+		{math.Ldexp(3, -26), 0x0001},
+		{math.Ldexp(1023, -24), 0x03ff},
+		{1 + math.Ldexp(1, -11), 0x3c00},
+		{1 + math.Ldexp(3, -11), 0x3c02},
+	} {
+		if h := toHalf(test.v); h != test.half {
+			t.Errorf("Half of %v should be %#04x, not %#04x", test.v, test.half, h)
+		}
+	}
+
+	if h := toHalf(math.NaN()); h&0x7c00 != 0x7c00 || h&0x3ff == 0 {
+		t.Errorf("Half of NaN should be NaN, not %#04x", h)
+	}
+}
+
+func TestHalfRoundTrip(t *testing.T) {
+	for h := 0; h < 0x10000; h++ {
+		if h&0x7c00 == 0x7c00 && h&0x3ff != 0 {
+			if v := fromHalf(uint16(h)); !math.IsNaN(v) {
+				t.Errorf("Half %#04x should be NaN, not %v", h, v)
+			}
+			continue
+		}
+		if back := toHalf(fromHalf(uint16(h))); back != uint16(h) {
+			t.Errorf("Half %#04x should round trip, not become %#04x", h, back)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package sdfio
+
+import (
+	"bufio"
+	"io"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+var ktx2Identifier = []byte{0xab, 'K', 'T', 'X', ' ', '2', '0', 0xbb, '\r', '\n', 0x1a, '\n'}
+
+// Vulkan formats of each TextureFormat
+var ktx2Formats = map[TextureFormat]uint32{
+	R8:   9,   // VK_FORMAT_R8_UNORM
+	R16F: 76,  // VK_FORMAT_R16_SFLOAT
+	R32F: 100, // VK_FORMAT_R32_SFLOAT
+	BC4:  139, // VK_FORMAT_BC4_UNORM_BLOCK
+}
+
+// WriteKTX2 writes a field as a single channel KTX 2.0 texture, with a mip chain generated by
+// repeatedly halving the field
+func WriteKTX2(w io.Writer, f *sdf.SDF, o TextureOptions) error {
+	o, err := o.validate(f)
+	if err != nil {
+		return err
+	}
+	levels := o.encodeLevels(f)
+
+	typeSize := uint32(o.blockSize())
+	if o.Format == BC4 {
+		typeSize = 1
+	}
+
+	// the header and index are followed by the index of each level and then the data format
+	// descriptor, after which the levels are stored smallest first
+	dfd := ktx2DFD(o.Format)
+	dfdOffset := 80 + 24*len(levels)
+	offset := dfdOffset + len(dfd)
+
+	// each level is aligned to both its block size and to 4 bytes
+	align := 4
+	if o.Format == BC4 {
+		align = 8
+	}
+	offsets := make([]int, len(levels))
+	for i := len(levels) - 1; i >= 0; i-- {
+		offset += (align - offset%align) % align
+		offsets[i] = offset
+		offset += len(levels[i].data)
+	}
+
+	header := make([]byte, 0, dfdOffset)
+	header = append(header, ktx2Identifier...)
+	for _, v := range []uint32{ktx2Formats[o.Format], typeSize, uint32(f.Width), uint32(f.Height), 0, 0, 1, uint32(len(levels)), 0} {
+		header = appendUint32(header, v)
+	}
+	header = appendUint32(header, uint32(dfdOffset))
+	header = appendUint32(header, uint32(len(dfd)))
+	header = appendUint32(header, 0) // no key/value data
+	header = appendUint32(header, 0)
+	header = appendUint64(header, 0) // no supercompression global data
+	header = appendUint64(header, 0)
+	for i, level := range levels {
+		header = appendUint64(header, uint64(offsets[i]))
+		header = appendUint64(header, uint64(len(level.data)))
+		header = appendUint64(header, uint64(len(level.data)))
+	}
+
+	bw := bufio.NewWriter(w)
+	written := 0
+	write := func(b []byte) {
+		if err == nil {
+			_, err = bw.Write(b)
+			written += len(b)
+		}
+	}
+	write(header)
+	write(dfd)
+	for i := len(levels) - 1; i >= 0; i-- {
+		write(make([]byte, offsets[i]-written))
+		write(levels[i].data)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ktx2DFD returns the data format descriptor of a format, made of its total size followed by a
+// basic descriptor block with one sample
+func ktx2DFD(format TextureFormat) []byte {
+	const (
+		modelRGBSDA = 1
+		modelBC4    = 131
+		float       = 0x80
+		signed      = 0x40
+	)
+
+	model, blockDim, channel := byte(modelRGBSDA), byte(0), byte(0)
+	var lower, upper uint32
+	bytesPlane := byte(0)
+	bits := 0
+	switch format {
+	case R8:
+		bytesPlane, bits, upper = 1, 8, math.MaxUint8
+	case R16F:
+		bytesPlane, bits, channel = 2, 16, float|signed
+		lower, upper = math.Float32bits(-1), math.Float32bits(1)
+	case R32F:
+		bytesPlane, bits, channel = 4, 32, float|signed
+		lower, upper = math.Float32bits(-1), math.Float32bits(1)
+	case BC4:
+		model, blockDim = modelBC4, 3
+		bytesPlane, bits, upper = 8, 64, math.MaxUint32
+	}
+
+	dfd := appendUint32(nil, 4+24+16)
+	dfd = appendUint32(dfd, 0)             // vendor and descriptor type
+	dfd = appendUint32(dfd, 2|(24+16)<<16) // version and block size
+	dfd = append(dfd, model, 1, 1, 0)      // BT.709 primaries, linear transfer, straight alpha
+	dfd = append(dfd, blockDim, blockDim, 0, 0)
+	dfd = append(dfd, bytesPlane, 0, 0, 0, 0, 0, 0, 0)
+
+	// the sample covers every bit of the block as the red channel
+	dfd = append(dfd, 0, 0, byte(bits-1), channel)
+	dfd = append(dfd, 0, 0, 0, 0)
+	dfd = appendUint32(dfd, lower)
+	dfd = appendUint32(dfd, upper)
+	return dfd
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
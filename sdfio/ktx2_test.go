@@ -0,0 +1,90 @@
+package sdfio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+func TestWriteKTX2(t *testing.T) {
+	for _, test := range []struct {
+		format   TextureFormat
+		vkFormat uint32
+		sizes    []int
+		align    int
+	}{
+		{R8, 9, []int{6 * 5, 3 * 2, 1}, 4},
+		{R16F, 76, []int{2 * 6 * 5, 2 * 3 * 2, 2}, 4},
+		{R32F, 100, []int{4 * 6 * 5, 4 * 3 * 2, 4}, 4},
+		{BC4, 139, []int{8 * 2 * 2, 8, 8}, 8},
+	} {
+		var buf bytes.Buffer
+		if err := WriteKTX2(&buf, sdf.New(6, 5), TextureOptions{Format: test.format}); err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		data := buf.Bytes()
+
+		if !bytes.HasPrefix(data, ktx2Identifier) {
+			t.Fatalf("File should start with the KTX 2.0 identifier, not %x", data[:12])
+		}
+
+		header := make([]uint32, 9)
+		binary.Read(bytes.NewReader(data[12:]), binary.LittleEndian, header)
+		expected := []uint32{test.vkFormat, header[1], 6, 5, 0, 0, 1, 3, 0}
+		for i := range header {
+			if header[i] != expected[i] {
+				t.Errorf("Header %v should be %v, not %v", header, expected, header)
+				break
+			}
+		}
+
+		dfdOffset := binary.LittleEndian.Uint32(data[48:])
+		dfdLength := binary.LittleEndian.Uint32(data[52:])
+		if dfdOffset != 80+24*3 || dfdLength != 44 || binary.LittleEndian.Uint32(data[dfdOffset:]) != 44 {
+			t.Errorf("Data format descriptor should be 44 bytes at offset %v, not %v bytes at %v", 80+24*3, dfdLength, dfdOffset)
+		}
+
+		end := 0
+		for i, size := range test.sizes {
+			offset := int(binary.LittleEndian.Uint64(data[80+24*i:]))
+			length := int(binary.LittleEndian.Uint64(data[88+24*i:]))
+			if length != size {
+				t.Errorf("Level %v of format %v should be %v bytes, not %v", i, test.format, size, length)
+			}
+			if offset%test.align != 0 {
+				t.Errorf("Level %v of format %v should be aligned to %v bytes, not at offset %v", i, test.format, test.align, offset)
+			}
+			if offset+length > end {
+				end = offset + length
+			}
+		}
+		if end != len(data) {
+			t.Errorf("Largest level should end the file at %v, not %v", len(data), end)
+		}
+	}
+}
+
+func TestWriteKTX2Levels(t *testing.T) {
+	f := sdf.New(4, 4)
+	for i := range f.Field {
+		f.Field[i] = float64(i % 4)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteKTX2(&buf, f, TextureOptions{Format: R32F, Levels: 2}); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	data := buf.Bytes()
+
+	// the smallest level is the average of each 2x2 block of pixels
+	offset := binary.LittleEndian.Uint64(data[80+24:])
+	level := make([]float32, 4)
+	binary.Read(bytes.NewReader(data[offset:]), binary.LittleEndian, level)
+	for i, expected := range []float32{0.5, 2.5, 0.5, 2.5} {
+		if level[i] != expected {
+			t.Errorf("Pixel %v of level 1 should be %v, not %v", i, expected, level[i])
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package sdfio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+const npyMagic = "\x93NUMPY"
+
+// WriteNPY writes a field as a NumPy .npy array of shape (height, width) in C order
+func WriteNPY(w io.Writer, f *sdf.SDF, p Precision) error {
+	var descr string
+	var size int
+	switch p {
+	case Float32:
+		descr, size = "<f4", 4
+	case Float64:
+		descr, size = "<f8", 8
+	case Half:
+		descr, size = "<f2", 2
+	default:
+		return errors.New("unknown precision")
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := writeNPYHeader(bw, descr, f.Height, f.Width); err != nil {
+		return err
+	}
+
+	value := make([]byte, size)
+	for _, v := range f.Field {
+		switch p {
+		case Float32:
+			binary.LittleEndian.PutUint32(value, math.Float32bits(float32(v)))
+		case Float64:
+			binary.LittleEndian.PutUint64(value, math.Float64bits(v))
+		default:
+			binary.LittleEndian.PutUint16(value, toHalf(v))
+		}
+		if _, err := bw.Write(value); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadNPY reads a field from a NumPy .npy array of shape (height, width). The array may hold
+// floats of 16, 32 or 64 bits in either byte order, and be in C or Fortran order.
+func ReadNPY(r io.Reader) (*sdf.SDF, error) {
+	header, err := readNPYHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(header.shape) != 2 {
+		return nil, fmt.Errorf("npy array should have 2 dimensions, not %v", len(header.shape))
+	}
+	h, w := header.shape[0], header.shape[1]
+	if err := checkSize(w, h); err != nil {
+		return nil, err
+	}
+
+	values, err := header.readFloats(r, w*h)
+	if err != nil {
+		return nil, err
+	}
+
+	f := sdf.New(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			f.Set(x, y, values[header.index(y, x, 0, w, h, 1)])
+		}
+	}
+	return f, nil
+}
+
+// WriteNPYNearest writes the nearest boundary point of every pixel of a DisplacementField as a
+// NumPy .npy array of 32-bit ints of shape (height, width, 2) in C order. The last axis holds
+// the X,Y coordinate of the point, which is (-1, -1) for pixels with no boundary.
+func WriteNPYNearest(w io.Writer, df *sdf.DisplacementField) error {
+	bw := bufio.NewWriter(w)
+	if err := writeNPYHeader(bw, "<i4", df.Height, df.Width, 2); err != nil {
+		return err
+	}
+
+	value := make([]byte, 8)
+	for y := 0; y < df.Height; y++ {
+		for x := 0; x < df.Width; x++ {
+			bx, by := df.NearestBoundaryAt(x, y)
+			binary.LittleEndian.PutUint32(value, uint32(int32(bx)))
+			binary.LittleEndian.PutUint32(value[4:], uint32(int32(by)))
+			if _, err := bw.Write(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadNPYNearest reads the nearest boundary points written by WriteNPYNearest and combines them
+// with the field they belong to. The array may hold ints of 16, 32 or 64 bits in either byte
+// order, and be in C or Fortran order.
+func ReadNPYNearest(r io.Reader, f *sdf.SDF) (*sdf.DisplacementField, error) {
+	header, err := readNPYHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(header.shape) != 3 || header.shape[0] != f.Height || header.shape[1] != f.Width || header.shape[2] != 2 {
+		return nil, fmt.Errorf("npy array should have shape (%v, %v, 2), not %v", f.Height, f.Width, header.shape)
+	}
+
+	values, err := header.readInts(r, f.Width*f.Height*2)
+	if err != nil {
+		return nil, err
+	}
+
+	df := sdf.NewDisplacementField(f.Width, f.Height)
+	copy(df.Field, f.Field)
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			bx := values[header.index(y, x, 0, f.Width, f.Height, 2)]
+			by := values[header.index(y, x, 1, f.Width, f.Height, 2)]
+			// a pixel with no boundary holds (-1, -1), and any other point must lie in the field
+			none := bx == -1 && by == -1
+			if !none && (bx < 0 || by < 0 || bx >= int64(f.Width) || by >= int64(f.Height)) {
+				return nil, fmt.Errorf("nearest boundary point (%v, %v) lies outside the field", bx, by)
+			}
+			df.SetNearestBoundary(x, y, int(bx), int(by))
+		}
+	}
+	return df, nil
+}
+
+// writeNPYHeader writes a version 1.0 header, padded so that the data is 64 byte aligned
+func writeNPYHeader(w io.Writer, descr string, shape ...int) error {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = strconv.Itoa(d)
+	}
+	dict := fmt.Sprintf("{'descr': '%v', 'fortran_order': False, 'shape': (%v), }", descr, strings.Join(dims, ", "))
+
+	// the magic, version and header length take 10 bytes and the header ends with a newline
+	padding := 63 - (10+len(dict))%64
+	dict += strings.Repeat(" ", padding) + "\n"
+	if len(dict) > math.MaxUint16 {
+		return errors.New("npy header is too long")
+	}
+
+	header := make([]byte, 10, 10+len(dict))
+	copy(header, npyMagic)
+	header[6], header[7] = 1, 0
+	binary.LittleEndian.PutUint16(header[8:], uint16(len(dict)))
+	_, err := w.Write(append(header, dict...))
+	return err
+}
+
+type npyHeader struct {
+	order        binary.ByteOrder
+	kind         byte // 'f' for floats, 'i' for ints
+	size         int
+	fortranOrder bool
+	shape        []int
+}
+
+var (
+	npyDescr   = regexp.MustCompile(`'descr'\s*:\s*'([<>|=])([a-z])(\d+)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+)
+
+func readNPYHeader(r io.Reader) (*npyHeader, error) {
+	prefix := make([]byte, 8)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, err
+	}
+	if string(prefix[:6]) != npyMagic {
+		return nil, errors.New("not an npy file")
+	}
+
+	// version 1 has a 2 byte header length and later versions a 4 byte length
+	var length int
+	switch major := prefix[6]; major {
+	case 1:
+		b := make([]byte, 2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		length = int(binary.LittleEndian.Uint16(b))
+	case 2, 3:
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		if length = int(binary.LittleEndian.Uint32(b)); length > 1<<20 {
+			return nil, errors.New("npy header is too long")
+		}
+	default:
+		return nil, fmt.Errorf("npy version %v is not supported", major)
+	}
+
+	dict := make([]byte, length)
+	if _, err := io.ReadFull(r, dict); err != nil {
+		return nil, err
+	}
+
+	h := &npyHeader{order: binary.LittleEndian}
+	descr := npyDescr.FindSubmatch(dict)
+	fortran := npyFortran.FindSubmatch(dict)
+	shape := npyShape.FindSubmatch(dict)
+	if descr == nil || fortran == nil || shape == nil {
+		return nil, fmt.Errorf("invalid npy header %q", bytes.TrimSpace(dict))
+	}
+
+	if descr[1][0] == '>' {
+		h.order = binary.BigEndian
+	}
+	h.kind = descr[2][0]
+	h.size, _ = strconv.Atoi(string(descr[3]))
+	h.fortranOrder = string(fortran[1]) == "True"
+
+	for _, dim := range strings.Split(string(shape[1]), ",") {
+		if dim = strings.TrimSpace(dim); dim == "" {
+			continue
+		}
+		d, err := strconv.Atoi(dim)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid npy shape (%s)", shape[1])
+		}
+		h.shape = append(h.shape, d)
+	}
+
+	return h, nil
+}
+
+// index returns the position of element [y, x, c] of an array of shape (h, w, channels)
+func (h *npyHeader) index(y, x, c, w, height, channels int) int {
+	if h.fortranOrder {
+		return y + height*(x+w*c)
+	}
+	return (y*w+x)*channels + c
+}
+
+func (h *npyHeader) readFloats(r io.Reader, n int) ([]float64, error) {
+	if h.kind != 'f' || (h.size != 2 && h.size != 4 && h.size != 8) {
+		return nil, errors.New("npy array should hold 16, 32 or 64-bit floats")
+	}
+
+	data, err := readFull(r, n*h.size)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		switch h.size {
+		case 2:
+			values[i] = fromHalf(h.order.Uint16(data[2*i:]))
+		case 4:
+			values[i] = float64(math.Float32frombits(h.order.Uint32(data[4*i:])))
+		default:
+			values[i] = math.Float64frombits(h.order.Uint64(data[8*i:]))
+		}
+	}
+	return values, nil
+}
+
+func (h *npyHeader) readInts(r io.Reader, n int) ([]int64, error) {
+	if h.kind != 'i' || (h.size != 2 && h.size != 4 && h.size != 8) {
+		return nil, errors.New("npy array should hold 16, 32 or 64-bit ints")
+	}
+
+	data, err := readFull(r, n*h.size)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]int64, n)
+	for i := range values {
+		switch h.size {
+		case 2:
+			values[i] = int64(int16(h.order.Uint16(data[2*i:])))
+		case 4:
+			values[i] = int64(int32(h.order.Uint32(data[4*i:])))
+		default:
+			values[i] = int64(h.order.Uint64(data[8*i:]))
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,149 @@
+package sdfio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+func TestNPYRoundTrip(t *testing.T) {
+	for _, p := range []Precision{Float32, Float64} {
+		f := testField()
+		f.Field[1] = math.Pi
+
+		var buf bytes.Buffer
+		if err := WriteNPY(&buf, f, p); err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+
+		read, err := ReadNPY(&buf)
+		if err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+
+		if p == Float32 {
+			f.Field[1] = float64(float32(math.Pi))
+		}
+		assertFieldsEqual(t, f, read)
+	}
+}
+
+func TestWriteNPYHeader(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteNPY(&buf, testField(), Float32); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("\x93NUMPY\x01\x00")) {
+		t.Fatalf("File should start with the version 1.0 magic, not %q", data[:8])
+	}
+
+	length := int(binary.LittleEndian.Uint16(data[8:]))
+	if (10+length)%64 != 0 {
+		t.Errorf("Header length %v should align the data to 64 bytes", length)
+	}
+	if len(data) != 10+length+4*15 {
+		t.Errorf("File should be %v bytes, not %v", 10+length+4*15, len(data))
+	}
+
+	header := string(data[10 : 10+length])
+	expected := "{'descr': '<f4', 'fortran_order': False, 'shape': (3, 5), }"
+	if strings.TrimRight(header, " \n") != expected || !strings.HasSuffix(header, "\n") {
+		t.Errorf("Header should be %q, not %q", expected, header)
+	}
+}
+
+// npyFile builds an .npy file from a header dictionary and its data
+func npyFile(dict string, data ...interface{}) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&buf, binary.LittleEndian, uint16(len(dict)))
+	buf.WriteString(dict)
+	for _, d := range data {
+		binary.Write(&buf, binary.BigEndian, d)
+	}
+	return buf.Bytes()
+}
+
+func TestReadNPYFortranBigEndian(t *testing.T) {
+	// a 2x3 array stored column by column as big-endian float64s
+	data := npyFile("{'descr': '>f8', 'fortran_order': True, 'shape': (2, 3), }\n",
+		[]float64{1, 4, 2, 5, 3, 6})
+
+	read, err := ReadNPY(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	f := sdf.New(3, 2)
+	copy(f.Field, []float64{1, 2, 3, 4, 5, 6})
+	assertFieldsEqual(t, f, read)
+}
+
+func TestReadNPYErrors(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		[]byte("\x93NUMPX\x01\x00\x00\x00"),
+		npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (3,), }\n"),
+		npyFile("{'descr': '<i4', 'fortran_order': False, 'shape': (1, 1), }\n", int32(0)),
+		npyFile("{'descr': '<f4', 'shape': (1, 1), }\n", float32(0)),
+		npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (2, 2), }\n", float32(0)),
+		npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (65536, 65536), }\n"),
+	} {
+		if _, err := ReadNPY(bytes.NewReader(data)); err == nil {
+			t.Errorf("Reading %q should return an error", data)
+		}
+	}
+}
+
+func TestNPYNearestRoundTrip(t *testing.T) {
+	df := sdf.NewDisplacementField(3, 2)
+	copy(df.Field, []float64{-1, 0, 1, 2, 3, 4})
+	df.SetNearestBoundary(0, 0, 1, 0)
+	df.SetNearestBoundary(1, 0, 1, 0)
+	df.SetNearestBoundary(2, 1, 2, 0)
+
+	var buf bytes.Buffer
+	if err := WriteNPYNearest(&buf, df); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	read, err := ReadNPYNearest(&buf, df.SDF)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	assertFieldsEqual(t, df.SDF, read.SDF)
+
+	for y := 0; y < df.Height; y++ {
+		for x := 0; x < df.Width; x++ {
+			ex, ey, eok := df.LookupNearestBoundary(x, y)
+			ax, ay, aok := read.LookupNearestBoundary(x, y)
+			if ax != ex || ay != ey || aok != eok {
+				t.Errorf("Nearest boundary of (%v, %v) should be (%v, %v, %v), not (%v, %v, %v)", x, y, ex, ey, eok, ax, ay, aok)
+			}
+		}
+	}
+}
+
+func TestReadNPYNearestErrors(t *testing.T) {
+	f := sdf.New(2, 1)
+	for _, data := range [][]byte{
+		npyFile("{'descr': '<i4', 'fortran_order': False, 'shape': (1, 2), }\n", []int32{0, 0}),
+		npyFile("{'descr': '<i4', 'fortran_order': False, 'shape': (2, 1, 2), }\n", []int32{0, 0, 0, 0}),
+		npyFile("{'descr': '<f4', 'fortran_order': False, 'shape': (1, 2, 2), }\n", []float32{0, 0, 0, 0}),
+		npyFile("{'descr': '>i4', 'fortran_order': False, 'shape': (1, 2, 2), }\n", []int32{0, 0, 2, 0}),
+		npyFile("{'descr': '>i4', 'fortran_order': False, 'shape': (1, 2, 2), }\n", []int32{0, 0}),
+		// only (-1, -1) marks a pixel with no boundary, so mixed points lie outside the field
+		npyFile("{'descr': '>i4', 'fortran_order': False, 'shape': (1, 2, 2), }\n", []int32{0, 0, -1, 0}),
+		npyFile("{'descr': '>i4', 'fortran_order': False, 'shape': (1, 2, 2), }\n", []int32{0, 0, 1, -1}),
+	} {
+		if _, err := ReadNPYNearest(bytes.NewReader(data), f); err == nil {
+			t.Errorf("Reading %q should return an error", data)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package sdfio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// WritePFM writes a field as a grayscale Portable Float Map of little-endian float32 distances.
+// As the format requires, rows are stored from the bottom of the field to the top.
+func WritePFM(w io.Writer, f *sdf.SDF) error {
+	bw := bufio.NewWriter(w)
+
+	// a negative scale marks the data as little-endian
+	if _, err := fmt.Fprintf(bw, "Pf\n%d %d\n-1.0\n", f.Width, f.Height); err != nil {
+		return err
+	}
+
+	row := make([]byte, 4*f.Width)
+	for y := f.Height - 1; y >= 0; y-- {
+		for x := 0; x < f.Width; x++ {
+			binary.LittleEndian.PutUint32(row[4*x:], math.Float32bits(float32(f.At(x, y))))
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadPFM reads a field from a grayscale Portable Float Map of either endianness
+func ReadPFM(r io.Reader) (*sdf.SDF, error) {
+	br := bufio.NewReader(r)
+
+	var magic string
+	var w, h int
+	var scale float64
+	if _, err := fmt.Fscan(br, &magic, &w, &h, &scale); err != nil {
+		return nil, fmt.Errorf("invalid PFM header: %v", err)
+	}
+	switch magic {
+	case "Pf":
+	case "PF":
+		return nil, errors.New("colour PFM files are not supported")
+	default:
+		return nil, errors.New("not a PFM file")
+	}
+	if err := checkSize(w, h); err != nil {
+		return nil, err
+	}
+
+	// exactly one whitespace character separates the header from the data
+	if _, err := br.ReadByte(); err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder = binary.BigEndian
+	if scale < 0 {
+		order = binary.LittleEndian
+	}
+
+	data, err := readFull(br, 4*w*h)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows are stored from the bottom of the image up
+	f := sdf.New(w, h)
+	for y := h - 1; y >= 0; y-- {
+		for x := 0; x < w; x++ {
+			f.Set(x, y, float64(math.Float32frombits(order.Uint32(data))))
+			data = data[4:]
+		}
+	}
+
+	return f, nil
+}
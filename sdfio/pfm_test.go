@@ -0,0 +1,73 @@
+package sdfio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPFMRoundTrip(t *testing.T) {
+	f := testField()
+
+	var buf bytes.Buffer
+	if err := WritePFM(&buf, f); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	read, err := ReadPFM(&buf)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	assertFieldsEqual(t, f, read)
+}
+
+func TestWritePFMLayout(t *testing.T) {
+	f := testField()
+
+	var buf bytes.Buffer
+	WritePFM(&buf, f)
+
+	header := "Pf\n5 3\n-1.0\n"
+	if !strings.HasPrefix(buf.String(), header) {
+		t.Fatalf("PFM should begin with the header %q", header)
+	}
+	if buf.Len() != len(header)+4*5*3 {
+		t.Errorf("PFM should hold 15 float32 values after the header, not %v bytes", buf.Len()-len(header))
+	}
+
+	// the first row stored is the bottom of the field
+	first := math.Float32frombits(binary.LittleEndian.Uint32(buf.Bytes()[len(header):]))
+	if float64(first) != f.At(0, 2) {
+		t.Errorf("First value stored should be the bottom left of the field, %v, not %v", f.At(0, 2), first)
+	}
+}
+
+func TestReadPFMBigEndian(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("Pf 2 1 1\n")
+	binary.Write(&buf, binary.BigEndian, []float32{1.5, -2})
+
+	f, err := ReadPFM(&buf)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if f.At(0, 0) != 1.5 || f.At(1, 0) != -2 {
+		t.Errorf("Big-endian values should be read as [1.5 -2], not %v", f.Field)
+	}
+}
+
+func TestReadPFMErrors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"P6\n2 2\n255\n",
+		"PF\n1 1\n-1\n000000000000",
+		"Pf\n0 1\n-1\n",
+		"Pf\n2 2\n-1\n0000",
+	} {
+		if _, err := ReadPFM(strings.NewReader(data)); err == nil {
+			t.Errorf("Reading %q should return an error", data)
+		}
+	}
+}
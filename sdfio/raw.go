@@ -0,0 +1,69 @@
+package sdfio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// rawMagic begins every raw field file
+const rawMagic = "SDFF"
+
+// WriteRaw writes a field as little-endian float32 distances in row-major order, after a
+// 12 byte header of the 4 bytes "SDFF" followed by the width and height as little-endian uint32s
+func WriteRaw(w io.Writer, f *sdf.SDF) error {
+	bw := bufio.NewWriter(w)
+
+	header := make([]byte, 12)
+	copy(header, rawMagic)
+	binary.LittleEndian.PutUint32(header[4:], uint32(f.Width))
+	binary.LittleEndian.PutUint32(header[8:], uint32(f.Height))
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	value := make([]byte, 4)
+	for _, v := range f.Field {
+		binary.LittleEndian.PutUint32(value, math.Float32bits(float32(v)))
+		if _, err := bw.Write(value); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadRaw reads a field written by WriteRaw
+func ReadRaw(r io.Reader) (*sdf.SDF, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != rawMagic {
+		return nil, errors.New("not a raw SDF file")
+	}
+
+	w := binary.LittleEndian.Uint32(header[4:])
+	h := binary.LittleEndian.Uint32(header[8:])
+	if w > math.MaxInt32 || h > math.MaxInt32 {
+		return nil, errors.New("field is too large")
+	}
+	if err := checkSize(int(w), int(h)); err != nil {
+		return nil, err
+	}
+
+	data, err := readFull(r, 4*int(w)*int(h))
+	if err != nil {
+		return nil, err
+	}
+	f := sdf.New(int(w), int(h))
+	for i := range f.Field {
+		f.Field[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:])))
+	}
+
+	return f, nil
+}
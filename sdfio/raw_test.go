@@ -0,0 +1,40 @@
+package sdfio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRawRoundTrip(t *testing.T) {
+	f := testField()
+
+	var buf bytes.Buffer
+	if err := WriteRaw(&buf, f); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	header := []byte{'S', 'D', 'F', 'F', 5, 0, 0, 0, 3, 0, 0, 0}
+	if !bytes.HasPrefix(buf.Bytes(), header) || buf.Len() != 12+4*15 {
+		t.Errorf("Raw file should be the 12 byte header followed by 15 float32 values")
+	}
+
+	read, err := ReadRaw(&buf)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	assertFieldsEqual(t, f, read)
+}
+
+func TestReadRawErrors(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		[]byte("SDFX\x01\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00"),
+		[]byte("SDFF\x00\x00\x00\x00\x01\x00\x00\x00"),
+		[]byte("SDFF\xff\xff\xff\x7f\xff\xff\xff\x7f"),
+		[]byte("SDFF\x02\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00"),
+	} {
+		if _, err := ReadRaw(bytes.NewReader(data)); err == nil {
+			t.Errorf("Reading %q should return an error", data)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package sdfio reads and writes Signed-Distance-Fields in file formats that keep more precision
+// than the 8-bit gray images of sdf.SDF.Draw, for use by other tools and for further processing.
+package sdfio
+
+import (
+	"errors"
+	"image/png"
+	"io"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// Precision selects the floating-point type that distances are stored as
+type Precision int
+
+const (
+	// Float32 stores distances as 32-bit floats
+	Float32 Precision = iota
+	// Float64 stores distances as 64-bit floats, without any loss of precision
+	Float64
+	// Half stores distances as 16-bit floats, which hold whole distances exactly up to 2048 but
+	// lose fractions as distances grow
+	Half
+)
+
+// checkSize rejects field dimensions that are not positive or exceed sdf.MaxPixels
+func checkSize(w, h int) error {
+	if w <= 0 || h <= 0 {
+		return errors.New("field dimensions must be positive")
+	}
+	if w > sdf.MaxPixels/h {
+		return errors.New("field is too large")
+	}
+	return nil
+}
+
+// readChunk is the most that readFull allocates ahead of the data arriving to fill it
+const readChunk = 1 << 20
+
+// readFull reads exactly n bytes from r. Unlike io.ReadFull it allocates memory only as the data
+// arrives, so a corrupt header that claims a huge size fails at the end of the data that is
+// actually there rather than allocating for the claimed size up front. Readers therefore read
+// their data with readFull before allocating the fields to hold it.
+func readFull(r io.Reader, n int) ([]byte, error) {
+	var data []byte
+	for len(data) < n {
+		k := n - len(data)
+		if k > readChunk {
+			k = readChunk
+		}
+		data = append(data, make([]byte, k)...)
+		if _, err := io.ReadFull(r, data[len(data)-k:]); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// WritePNG writes a field as an 8 or 16-bit grayscale PNG using the given Encoding
+func WritePNG(w io.Writer, f *sdf.SDF, e sdf.Encoding) error {
+	img, err := f.DrawEncoded(e)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// ReadPNG reads a field from an 8 or 16-bit grayscale PNG written with the given Encoding
+func ReadPNG(r io.Reader, e sdf.Encoding) (*sdf.SDF, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return sdf.Decode(img, e)
+}
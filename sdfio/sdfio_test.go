@@ -0,0 +1,99 @@
+package sdfio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// testField returns a small field with distinct values, including an infinite distance
+func testField() *sdf.SDF {
+	f := sdf.New(5, 3)
+	for i := range f.Field {
+		f.Field[i] = float64(i)*1.25 - 7.5
+	}
+	f.Field[4] = math.Inf(1)
+	return f
+}
+
+func TestPNGRoundTrip(t *testing.T) {
+	f := testField()
+	e := sdf.Encoding{Spread: 16, ZeroLevel: 0.5, BitDepth: 16}
+
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, f, e); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	read, err := ReadPNG(&buf, e)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	for i, v := range f.Field {
+		expected := math.Max(-16, math.Min(16, v))
+		if math.Abs(read.Field[i]-expected) > 16.0/math.MaxUint16 {
+			t.Errorf("Distance %v should be read back as %v, not %v", v, expected, read.Field[i])
+		}
+	}
+}
+
+func TestWritePNGInvalidEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, testField(), sdf.Encoding{}); err == nil {
+		t.Errorf("Invalid encoding should return an error")
+	}
+}
+
+func assertFieldsEqual(t *testing.T, expected, actual *sdf.SDF) {
+	t.Helper()
+	if actual.Width != expected.Width || actual.Height != expected.Height {
+		t.Fatalf("Field size should be (%v, %v), not (%v, %v)", expected.Width, expected.Height, actual.Width, actual.Height)
+	}
+	for i, v := range expected.Field {
+		if actual.Field[i] != v {
+			t.Errorf("Value %v should be %v, not %v", i, v, actual.Field[i])
+		}
+	}
+}
+
+func TestReadTruncatedLargeField(t *testing.T) {
+	// headers claiming a 8192*8192 field with just a few bytes of data behind them
+	var npy bytes.Buffer
+	if err := writeNPYHeader(&npy, "<f4", 8192, 8192); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	for name, test := range map[string]struct {
+		data []byte
+		read func(r io.Reader) error
+	}{
+		"raw": {[]byte("SDFF\x00\x20\x00\x00\x00\x20\x00\x00\x00\x00\x00\x00"), func(r io.Reader) error {
+			_, err := ReadRaw(r)
+			return err
+		}},
+		"pfm": {[]byte("Pf\n8192 8192\n-1\n\x00\x00\x00\x00"), func(r io.Reader) error {
+			_, err := ReadPFM(r)
+			return err
+		}},
+		"npy": {append(npy.Bytes(), 0, 0, 0, 0), func(r io.Reader) error {
+			_, err := ReadNPY(r)
+			return err
+		}},
+	} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := test.read(bytes.NewReader(test.data))
+		runtime.ReadMemStats(&after)
+
+		if err == nil {
+			t.Errorf("Reading a truncated %v field should return an error", name)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16<<20 {
+			t.Errorf("Reading a truncated %v field should not allocate for the whole field, not %v bytes", name, allocated)
+		}
+	}
+}
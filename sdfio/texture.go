@@ -0,0 +1,205 @@
+package sdfio
+
+import (
+	"errors"
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// TextureFormat selects the pixel format of a GPU texture
+type TextureFormat int
+
+const (
+	// R8 stores gray levels as 8-bit unsigned normalized values, mapped with the Encoding
+	R8 TextureFormat = iota
+	// R16F stores distances as 16-bit floats
+	R16F
+	// R32F stores distances as 32-bit floats
+	R32F
+	// BC4 stores gray levels, mapped with the Encoding, block compressed to 4 bits per pixel
+	BC4
+)
+
+// TextureOptions controls how a field is written as a GPU texture
+type TextureOptions struct {
+	Format TextureFormat
+	// Encoding maps distances to gray levels for the R8 and BC4 formats. The zero Encoding
+	// uses sdf.DrawEncoding, as Draw does. The BitDepth of the Encoding is ignored.
+	Encoding sdf.Encoding
+	// Levels is the number of mip levels to write, or 0 for a full chain down to 1x1
+	Levels int
+}
+
+// textureLevel is the encoded pixels of one mip level
+type textureLevel struct {
+	width, height int
+	data          []byte
+}
+
+// validate checks the options and returns them with the defaults filled in
+func (o TextureOptions) validate(f *sdf.SDF) (TextureOptions, error) {
+	if f.Width <= 0 || f.Height <= 0 {
+		return o, errors.New("field dimensions must be positive")
+	}
+	if o.Format < R8 || o.Format > BC4 {
+		return o, errors.New("unknown texture format")
+	}
+
+	if o.Encoding == (sdf.Encoding{}) {
+		o.Encoding = sdf.DrawEncoding
+	}
+	o.Encoding.BitDepth = 8
+	if err := o.Encoding.Validate(); err != nil {
+		return o, err
+	}
+
+	full := 1
+	for w, h := f.Width, f.Height; w > 1 || h > 1; w, h = w/2, h/2 {
+		full++
+	}
+	if o.Levels == 0 {
+		o.Levels = full
+	}
+	if o.Levels < 0 || o.Levels > full {
+		return o, errors.New("texture levels must be between 0 and the length of a full mip chain")
+	}
+	return o, nil
+}
+
+// encodeLevels returns the encoded mip chain of the field, largest level first
+func (o TextureOptions) encodeLevels(f *sdf.SDF) []textureLevel {
+	levels := make([]textureLevel, o.Levels)
+	for i := range levels {
+		if i > 0 {
+			f = downsample(f)
+		}
+		levels[i] = textureLevel{f.Width, f.Height, o.encode(f)}
+	}
+	return levels
+}
+
+// blockSize returns the bytes per pixel, or per 4x4 block for BC4
+func (o TextureOptions) blockSize() int {
+	switch o.Format {
+	case R16F:
+		return 2
+	case R32F:
+		return 4
+	case BC4:
+		return 8
+	}
+	return 1
+}
+
+func (o TextureOptions) encode(f *sdf.SDF) []byte {
+	var data []byte
+	switch o.Format {
+	case R8:
+		for _, d := range f.Field {
+			data = append(data, uint8(math.Round(o.Encoding.Encode(d)*math.MaxUint8)))
+		}
+	case R16F:
+		for _, d := range f.Field {
+			data = appendUint16(data, toHalf(d))
+		}
+	case R32F:
+		for _, d := range f.Field {
+			data = appendUint32(data, math.Float32bits(float32(d)))
+		}
+	case BC4:
+		data = encodeBC4(f, o.Encoding)
+	}
+	return data
+}
+
+// downsample returns the next mip level of a field, half its size. Distances stay measured in
+// pixels of the largest level so that every level decodes alike.
+func downsample(f *sdf.SDF) *sdf.SDF {
+	w, h := f.Width/2, f.Height/2
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	// sampling bilinearly at the corners shared by 2x2 pixels averages them
+	sx, sy := float64(f.Width)/float64(w), float64(f.Height)/float64(h)
+	next := sdf.New(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			next.Set(x, y, f.Sample((float64(x)+0.5)*sx, (float64(y)+0.5)*sy))
+		}
+	}
+	return next
+}
+
+// encodeBC4 compresses the gray levels of a field into 8 byte blocks of 4x4 pixels, in rows of
+// blocks. Blocks that overhang the field repeat its edge pixels.
+func encodeBC4(f *sdf.SDF, e sdf.Encoding) []byte {
+	bw, bh := (f.Width+3)/4, (f.Height+3)/4
+	data := make([]byte, 0, 8*bw*bh)
+
+	var block [16]float64
+	for by := 0; by < bh; by++ {
+		for bx := 0; bx < bw; bx++ {
+			for i := range block {
+				x := minInt(4*bx+i%4, f.Width-1)
+				y := minInt(4*by+i/4, f.Height-1)
+				block[i] = e.Encode(f.At(x, y)) * math.MaxUint8
+			}
+			data = append(data, bc4Block(block)...)
+		}
+	}
+	return data
+}
+
+// bc4Block compresses 16 gray levels in range [0, 255] to the two endpoints of a palette of 8
+// levels followed by a 3-bit palette index per pixel
+func bc4Block(block [16]float64) []byte {
+	lo, hi := block[0], block[0]
+	for _, v := range block {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	red0, red1 := uint8(math.Round(hi)), uint8(math.Round(lo))
+
+	// with red0 > red1 the palette interpolates 6 levels between the endpoints, otherwise the
+	// block is flat and every pixel uses red0
+	var indices uint64
+	if red0 > red1 {
+		palette := bc4Palette(red0, red1)
+		for i, v := range block {
+			best := 0
+			for j := range palette {
+				if math.Abs(v-palette[j]) < math.Abs(v-palette[best]) {
+					best = j
+				}
+			}
+			indices |= uint64(best) << (3 * uint(i))
+		}
+	}
+
+	b := []byte{red0, red1}
+	for i := 0; i < 6; i++ {
+		b = append(b, byte(indices>>(8*uint(i))))
+	}
+	return b
+}
+
+// bc4Palette returns the 8 levels of a block whose red0 is greater than its red1
+func bc4Palette(red0, red1 uint8) [8]float64 {
+	r0, r1 := float64(red0), float64(red1)
+	palette := [8]float64{r0, r1}
+	for i := 2; i < 8; i++ {
+		palette[i] = ((8-float64(i))*r0 + (float64(i)-1)*r1) / 7
+	}
+	return palette
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
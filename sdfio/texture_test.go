@@ -0,0 +1,130 @@
+package sdfio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// decodeBC4 returns the gray levels of the 16 pixels of a block
+func decodeBC4(block []byte) [16]float64 {
+	red0, red1 := block[0], block[1]
+	var palette [8]float64
+	if red0 > red1 {
+		palette = bc4Palette(red0, red1)
+	} else {
+		r0, r1 := float64(red0), float64(red1)
+		palette = [8]float64{r0, r1}
+		for i := 2; i < 6; i++ {
+			palette[i] = ((6-float64(i))*r0 + (float64(i)-1)*r1) / 5
+		}
+		palette[6], palette[7] = 0, 255
+	}
+
+	var indices uint64
+	for i := 0; i < 6; i++ {
+		indices |= uint64(block[2+i]) << (8 * uint(i))
+	}
+
+	var levels [16]float64
+	for i := range levels {
+		levels[i] = palette[indices>>(3*uint(i))&7]
+	}
+	return levels
+}
+
+func TestEncodeBC4(t *testing.T) {
+	f := sdf.New(6, 5)
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			f.Set(x, y, math.Hypot(float64(x)-2, float64(y)-2)-2)
+		}
+	}
+	e := sdf.Encoding{Spread: 4, ZeroLevel: 0.5, BitDepth: 8}
+
+	data := encodeBC4(f, e)
+	if len(data) != 2*2*8 {
+		t.Fatalf("6x5 field should compress to 4 blocks of 8 bytes, not %v bytes", len(data))
+	}
+
+	for by := 0; by < 2; by++ {
+		for bx := 0; bx < 2; bx++ {
+			block := 2*by + bx
+			levels := decodeBC4(data[8*block : 8*block+8])
+			for i, level := range levels {
+				x, y := 4*bx+i%4, 4*by+i/4
+				if x >= f.Width || y >= f.Height {
+					continue
+				}
+				// the 8 levels of the palette span at most the full range of gray
+				expected := e.Encode(f.At(x, y)) * 255
+				if math.Abs(level-expected) > 255.0/14+0.5 {
+					t.Errorf("Gray level of (%v, %v) should be about %v, not %v", x, y, expected, level)
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeBC4Flat(t *testing.T) {
+	f := sdf.New(4, 4)
+	levels := decodeBC4(encodeBC4(f, sdf.DrawEncoding))
+	for i, level := range levels {
+		if level != 127 {
+			t.Errorf("Gray level of pixel %v should be 127, not %v", i, level)
+		}
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	f := sdf.New(4, 3)
+	for i := range f.Field {
+		f.Field[i] = float64(i)
+	}
+
+	next := downsample(f)
+	if next.Width != 2 || next.Height != 1 {
+		t.Fatalf("Next level should be 2x1, not %vx%v", next.Width, next.Height)
+	}
+
+	// the middle row of 3 rows is at the centre of the level
+	for x, expected := range []float64{4.5, 6.5} {
+		if v := next.At(x, 0); v != expected {
+			t.Errorf("Pixel %v should be %v, not %v", x, expected, v)
+		}
+	}
+}
+
+func TestTextureOptionsLevels(t *testing.T) {
+	f := sdf.New(16, 5)
+	for _, test := range []struct {
+		levels, expected int
+	}{
+		{0, 5},
+		{1, 1},
+		{5, 5},
+	} {
+		o, err := TextureOptions{Levels: test.levels}.validate(f)
+		if err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		if o.Levels != test.expected {
+			t.Errorf("%v levels should become %v, not %v", test.levels, test.expected, o.Levels)
+		}
+	}
+
+	sizes := [][2]int{{16, 5}, {8, 2}, {4, 1}, {2, 1}, {1, 1}}
+	o, _ := TextureOptions{Format: R32F}.validate(f)
+	for i, level := range o.encodeLevels(f) {
+		if level.width != sizes[i][0] || level.height != sizes[i][1] || len(level.data) != 4*level.width*level.height {
+			t.Errorf("Level %v should be %vx%v, not %vx%v with %v bytes", i, sizes[i][0], sizes[i][1], level.width, level.height, len(level.data))
+		}
+	}
+
+	for _, o := range []TextureOptions{{Levels: 6}, {Levels: -1}, {Format: BC4 + 1}, {Encoding: sdf.Encoding{Spread: -1}}} {
+		if _, err := o.validate(f); err == nil {
+			t.Errorf("Options %+v should return an error", o)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package shapes
+
+import (
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Union returns the region inside either a or b
+func Union(a, b Shape) Shape {
+	return combine(a, b, math.Min)
+}
+
+// Intersection returns the region inside both a and b
+func Intersection(a, b Shape) Shape {
+	return combine(a, b, math.Max)
+}
+
+// Subtraction returns the region inside a but not inside b
+func Subtraction(a, b Shape) Shape {
+	return combine(a, b, sdf.SubtractDistance)
+}
+
+// Xor returns the region inside exactly one of a and b
+func Xor(a, b Shape) Shape {
+	return combine(a, b, sdf.XorDistance)
+}
+
+// SmoothUnion returns the union of a and b with the seams where they meet blended over a
+// distance of about k, using the given smooth minimum
+func SmoothUnion(a, b Shape, k float64, smin sdf.SmoothMin) Shape {
+	return combine(a, b, func(da, db float64) float64 {
+		return smin(da, db, k)
+	})
+}
+
+// SmoothIntersection returns the intersection of a and b with the creases where they meet
+// blended over a distance of about k, using the given smooth minimum
+func SmoothIntersection(a, b Shape, k float64, smin sdf.SmoothMin) Shape {
+	return combine(a, b, func(da, db float64) float64 {
+		return -smin(-da, -db, k)
+	})
+}
+
+// SmoothSubtraction returns a with b cut out of it, with the creases where they meet blended
+// over a distance of about k, using the given smooth minimum
+func SmoothSubtraction(a, b Shape, k float64, smin sdf.SmoothMin) Shape {
+	return combine(a, b, func(da, db float64) float64 {
+		return -smin(-da, db, k)
+	})
+}
+
+func combine(a, b Shape, f func(da, db float64) float64) Shape {
+	return Func(func(p vector.Vec) float64 {
+		return f(a.SignedDistance(p), b.SignedDistance(p))
+	})
+}
@@ -0,0 +1,59 @@
+package shapes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+func TestCSG(t *testing.T) {
+	a := Circle{v(0, 0), 2}
+	b := Circle{v(3, 0), 2}
+	d := math.Hypot(1.5, 0.5) - 2 // the distance to both circles at (1.5, 0.5)
+
+	tests := []struct {
+		name     string
+		shape    Shape
+		p        vector.Vec
+		expected float64
+	}{
+		{"union in a", Union(a, b), v(-1, 0), -1},
+		{"union in b", Union(a, b), v(4, 0), -1},
+		{"intersection", Intersection(a, b), v(1.5, 0), -0.5},
+		{"intersection outside", Intersection(a, b), v(-1, 0), 2},
+		{"subtraction", Subtraction(a, b), v(-1, 0), -1},
+		{"subtraction in b", Subtraction(a, b), v(1.5, 0), 0.5},
+		{"xor overlap", Xor(a, b), v(1.5, 0), 0.5},
+		{"xor in a", Xor(a, b), v(-1, 0), -1},
+		{"smooth union far", SmoothUnion(a, b, 1, sdf.PolySmoothMin), v(-1, 0), -1},
+		{"smooth union seam", SmoothUnion(a, b, 1, sdf.PolySmoothMin), v(1.5, 2), math.Hypot(1.5, 2) - 2 - 0.25},
+		{"smooth intersection", SmoothIntersection(a, b, 1, sdf.PolySmoothMin), v(1.5, 0), -0.5 + 0.25},
+		{"smooth subtraction", SmoothSubtraction(a, b, 1, sdf.PolySmoothMin), v(1.5, 0.5), -d + (1+2*d)*(1+2*d)/4},
+	}
+
+	for _, tt := range tests {
+		if d := tt.shape.SignedDistance(tt.p); math.Abs(d-tt.expected) > 1e-9 {
+			t.Errorf("%v: distance at %v should be %v, not %v", tt.name, tt.p, tt.expected, d)
+		}
+	}
+}
+
+func TestCSGMatchesGrid(t *testing.T) {
+	a := Box{v(6, 6), v(4, 3)}
+	b := Circle{v(10, 8), 3}
+
+	gridA, gridB := Bake(a, 16, 16), Bake(b, 16, 16)
+	grid, err := sdf.SmoothUnion(gridA, gridB, 2, sdf.ExpSmoothMin)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	analytic := Bake(SmoothUnion(a, b, 2, sdf.ExpSmoothMin), 16, 16)
+
+	for i := range grid.Field {
+		if grid.Field[i] != analytic.Field[i] {
+			t.Fatalf("Baked analytic CSG should match CSG of the baked fields")
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package shapes
+
+import (
+	"math"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Ellipse is an axis-aligned ellipse
+type Ellipse struct {
+	Centre vector.Vec
+	// Radii are the semi-axes of the ellipse in x and y
+	Radii vector.Vec
+}
+
+// SignedDistance returns the exact signed distance from p to the ellipse's edge
+func (e Ellipse) SignedDistance(p vector.Vec) float64 {
+	// by symmetry only the first quadrant is needed, with the major axis along x
+	y0, y1 := math.Abs(p.X-e.Centre.X), math.Abs(p.Y-e.Centre.Y)
+	e0, e1 := math.Abs(e.Radii.X), math.Abs(e.Radii.Y)
+	if e0 < e1 {
+		y0, y1, e0, e1 = y1, y0, e1, e0
+	}
+
+	if e1 == 0 {
+		// degenerate to a segment along the major axis
+		return segmentDistance(vector.Vec{X: y0, Y: y1}, vector.Vec{}, vector.Vec{X: e0})
+	}
+
+	dist := ellipseDistance(e0, e1, y0, y1)
+	if (y0/e0)*(y0/e0)+(y1/e1)*(y1/e1) < 1 {
+		return -dist
+	}
+	return dist
+}
+
+// ellipseDistance finds the unsigned distance from (y0, y1) to the ellipse with semi-axes
+// e0 >= e1 > 0, for y0, y1 >= 0. The nearest point is found by bisecting for the root of a
+// monotonic function as described by Eberly in "Distance from a Point to an Ellipse".
+func ellipseDistance(e0, e1, y0, y1 float64) float64 {
+	if y1 > 0 {
+		if y0 > 0 {
+			z0, z1 := y0/e0, y1/e1
+			g := z0*z0 + z1*z1 - 1
+			if g == 0 {
+				return 0
+			}
+			r0 := (e0 / e1) * (e0 / e1)
+			s := ellipseRoot(r0, z0, z1, g)
+			x0, x1 := r0*y0/(s+r0), y1/(s+1)
+			return math.Hypot(x0-y0, x1-y1)
+		}
+		return math.Abs(y1 - e1)
+	}
+
+	// on the major axis the nearest point is either off the axis or at its end
+	numer, denom := e0*y0, e0*e0-e1*e1
+	if numer < denom {
+		xde0 := numer / denom
+		x0, x1 := e0*xde0, e1*math.Sqrt(1-xde0*xde0)
+		return math.Hypot(x0-y0, x1)
+	}
+	return math.Abs(y0 - e0)
+}
+
+func ellipseRoot(r0, z0, z1, g float64) float64 {
+	n0 := r0 * z0
+	s0, s1 := z1-1, 0.0
+	if g > 0 {
+		s1 = math.Hypot(n0, z1) - 1
+	}
+
+	s := s0
+	for i := 0; i < 200; i++ {
+		s = (s0 + s1) / 2
+		if s == s0 || s == s1 {
+			break
+		}
+		ratio0, ratio1 := n0/(s+r0), z1/(s+1)
+		g = ratio0*ratio0 + ratio1*ratio1 - 1
+		switch {
+		case g > 0:
+			s0 = s
+		case g < 0:
+			s1 = s
+		default:
+			return s
+		}
+	}
+	return s
+}
@@ -0,0 +1,173 @@
+package shapes
+
+import (
+	"math"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Circle is a disc
+type Circle struct {
+	Centre vector.Vec
+	Radius float64
+}
+
+// SignedDistance returns the signed distance from p to the circle's edge
+func (c Circle) SignedDistance(p vector.Vec) float64 {
+	return p.Sub(c.Centre).Len() - c.Radius
+}
+
+// Box is an axis-aligned rectangle
+type Box struct {
+	Centre vector.Vec
+	// HalfSize is half of the width and height of the box
+	HalfSize vector.Vec
+}
+
+// SignedDistance returns the signed distance from p to the box's edge
+func (b Box) SignedDistance(p vector.Vec) float64 {
+	return boxDistance(p.Sub(b.Centre), b.HalfSize)
+}
+
+func boxDistance(p, halfSize vector.Vec) float64 {
+	dx := math.Abs(p.X) - halfSize.X
+	dy := math.Abs(p.Y) - halfSize.Y
+
+	// the distance outside comes from the corner or an edge, inside from the nearest edge
+	outside := math.Hypot(math.Max(dx, 0), math.Max(dy, 0))
+	inside := math.Min(math.Max(dx, dy), 0)
+	return outside + inside
+}
+
+// RoundedBox is an axis-aligned rectangle with circular corners
+type RoundedBox struct {
+	Centre vector.Vec
+	// HalfSize is half of the width and height of the box, including its corners
+	HalfSize vector.Vec
+	// Radius of the corners, which is limited to half of the smaller side
+	Radius float64
+}
+
+// SignedDistance returns the signed distance from p to the rounded box's edge
+func (b RoundedBox) SignedDistance(p vector.Vec) float64 {
+	r := math.Max(0, math.Min(b.Radius, math.Min(b.HalfSize.X, b.HalfSize.Y)))
+	return boxDistance(p.Sub(b.Centre), vector.Vec{X: b.HalfSize.X - r, Y: b.HalfSize.Y - r}) - r
+}
+
+// Segment is the straight line between two points. It has no interior, so its distance is never
+// negative.
+type Segment struct {
+	A, B vector.Vec
+}
+
+// SignedDistance returns the distance from p to the segment
+func (s Segment) SignedDistance(p vector.Vec) float64 {
+	return segmentDistance(p, s.A, s.B)
+}
+
+func segmentDistance(p, a, b vector.Vec) float64 {
+	ab, ap := b.Sub(a), p.Sub(a)
+	t := 0.0
+	if l := ab.Dot(ab); l > 0 {
+		t = math.Max(0, math.Min(1, ap.Dot(ab)/l))
+	}
+	return ap.Sub(ab.Scale(t)).Len()
+}
+
+// Capsule is a segment thickened by a radius, giving a stadium with rounded ends
+type Capsule struct {
+	A, B   vector.Vec
+	Radius float64
+}
+
+// SignedDistance returns the signed distance from p to the capsule's edge
+func (c Capsule) SignedDistance(p vector.Vec) float64 {
+	return segmentDistance(p, c.A, c.B) - c.Radius
+}
+
+// Triangle is the region between three points, in either winding order
+type Triangle struct {
+	A, B, C vector.Vec
+}
+
+// SignedDistance returns the signed distance from p to the triangle's edge
+func (t Triangle) SignedDistance(p vector.Vec) float64 {
+	return Polygon{t.A, t.B, t.C}.SignedDistance(p)
+}
+
+// Polygon is the region enclosed by a closed loop of points, in either winding order. Self
+// intersecting polygons follow the even-odd fill rule.
+type Polygon []vector.Vec
+
+// SignedDistance returns the signed distance from p to the polygon's edge
+func (poly Polygon) SignedDistance(p vector.Vec) float64 {
+	if len(poly) == 0 {
+		return math.Inf(1)
+	}
+
+	dSq := math.Inf(1)
+	inside := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[j], poly[i]
+		d := segmentDistance(p, a, b)
+		dSq = math.Min(dSq, d*d)
+
+		// count crossings of a ray heading in +x, half-open in y so that vertices count once
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)/(b.Y-a.Y)*(b.X-a.X)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+
+	if inside {
+		return -math.Sqrt(dSq)
+	}
+	return math.Sqrt(dSq)
+}
+
+// Arc is a stroke of the given thickness along part of a circle, with rounded ends. A zero
+// thickness arc is a curve with no interior.
+type Arc struct {
+	Centre vector.Vec
+	Radius float64
+	// Start is the angle in radians where the arc begins, measured from +x towards +y
+	Start float64
+	// Sweep is the angle in radians the arc covers from its start, negative to go backwards
+	Sweep float64
+	// Thickness is the full width of the stroke
+	Thickness float64
+}
+
+// SignedDistance returns the signed distance from p to the edge of the arc's stroke
+func (a Arc) SignedDistance(p vector.Vec) float64 {
+	start, sweep := a.Start, a.Sweep
+	if sweep < 0 {
+		start, sweep = start+sweep, -sweep
+	}
+
+	d := p.Sub(a.Centre)
+	var dist float64
+	if sweep >= 2*math.Pi {
+		dist = math.Abs(d.Len() - a.Radius)
+	} else {
+		// the angle of p past the start of the arc, in [0, 2π)
+		t := math.Mod(math.Atan2(d.Y, d.X)-start, 2*math.Pi)
+		if t < 0 {
+			t += 2 * math.Pi
+		}
+
+		if t <= sweep {
+			dist = math.Abs(d.Len() - a.Radius)
+		} else {
+			// beyond the ends the nearest point is whichever end is closer
+			end := start + sweep
+			p0 := vector.Vec{X: math.Cos(start), Y: math.Sin(start)}.Scale(a.Radius)
+			p1 := vector.Vec{X: math.Cos(end), Y: math.Sin(end)}.Scale(a.Radius)
+			dist = math.Min(d.Sub(p0).Len(), d.Sub(p1).Len())
+		}
+	}
+
+	return dist - a.Thickness/2
+}
@@ -0,0 +1,31 @@
+// Package shapes provides analytic 2D signed distance functions for simple primitives, which can
+// be evaluated at any point and baked into an sdf.SDF of any size.
+//
+// As with the rest of this module distances are negative inside a shape and positive outside,
+// and coordinates follow images with y pointing down and the centre of the pixel at (x, y)
+// lying at (x+0.5, y+0.5).
+package shapes
+
+import (
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Shape is a region of the plane described by its signed distance function. A *vector.Shape
+// is also a Shape.
+type Shape interface {
+	SignedDistance(p vector.Vec) float64
+}
+
+// Func adapts an ordinary function to a Shape
+type Func func(p vector.Vec) float64
+
+// SignedDistance returns f(p)
+func (f Func) SignedDistance(p vector.Vec) float64 {
+	return f(p)
+}
+
+// Bake evaluates the shape at the centre of every pixel of an SDF of the given size
+func Bake(s Shape, w, h int) *sdf.SDF {
+	return vector.Bake(s.SignedDistance, w, h)
+}
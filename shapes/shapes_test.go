@@ -0,0 +1,111 @@
+package shapes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+var _ Shape = (*vector.Shape)(nil)
+
+func v(x, y float64) vector.Vec {
+	return vector.Vec{X: x, Y: y}
+}
+
+func TestPrimitives(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    Shape
+		p        vector.Vec
+		expected float64
+	}{
+		{"circle inside", Circle{v(1, 2), 3}, v(1, 2), -3},
+		{"circle outside", Circle{v(1, 2), 3}, v(1, 7), 2},
+		{"box inside", Box{v(0, 0), v(4, 2)}, v(3, 0), -1},
+		{"box edge", Box{v(0, 0), v(4, 2)}, v(0, 5), 3},
+		{"box corner", Box{v(0, 0), v(4, 2)}, v(7, 6), 5},
+		{"rounded box edge", RoundedBox{v(0, 0), v(4, 2), 1}, v(0, 3), 1},
+		{"rounded box corner", RoundedBox{v(0, 0), v(4, 2), 1}, v(6, 4), math.Sqrt(18) - 1},
+		{"rounded box clamps radius", RoundedBox{v(0, 0), v(4, 2), 10}, v(0, 0), -2},
+		{"segment side", Segment{v(0, 0), v(4, 0)}, v(2, -3), 3},
+		{"segment end", Segment{v(0, 0), v(4, 0)}, v(7, 4), 5},
+		{"segment on", Segment{v(0, 0), v(4, 0)}, v(1, 0), 0},
+		{"degenerate segment", Segment{v(1, 1), v(1, 1)}, v(4, 5), 5},
+		{"capsule", Capsule{v(0, 0), v(4, 0), 1}, v(2, 0), -1},
+		{"capsule end", Capsule{v(0, 0), v(4, 0), 1}, v(6, 0), 1},
+		{"triangle inside", Triangle{v(0, 0), v(6, 0), v(0, 6)}, v(1, 2), -1},
+		{"triangle outside", Triangle{v(0, 0), v(6, 0), v(0, 6)}, v(-2, 3), 2},
+		{"triangle reversed", Triangle{v(0, 6), v(6, 0), v(0, 0)}, v(1, 2), -1},
+		{"polygon inside", Polygon{v(0, 0), v(4, 0), v(4, 4), v(2, 2), v(0, 4)}, v(1, 1), -1},
+		{"polygon notch", Polygon{v(0, 0), v(4, 0), v(4, 4), v(2, 2), v(0, 4)}, v(2, 4), math.Sqrt(2)},
+		{"empty polygon", Polygon{}, v(0, 0), math.Inf(1)},
+		{"ellipse centre", Ellipse{v(1, 1), v(4, 2)}, v(1, 1), -2},
+		{"ellipse major axis", Ellipse{v(0, 0), v(4, 2)}, v(6, 0), 2},
+		{"ellipse minor axis", Ellipse{v(0, 0), v(2, 4)}, v(-3, 0), 1},
+		{"ellipse circle", Ellipse{v(0, 0), v(3, 3)}, v(3, 4), 2},
+		{"flat ellipse", Ellipse{v(0, 0), v(4, 0)}, v(2, 3), 3},
+		{"arc", Arc{v(0, 0), 5, 0, math.Pi / 2, 2}, v(0, 5), -1},
+		{"arc middle", Arc{v(0, 0), 5, 0, math.Pi / 2, 2}, v(3, 4).Scale(2), 4},
+		{"arc gap", Arc{v(0, 0), 5, 0, math.Pi / 2, 2}, v(-5, 0), math.Sqrt(50) - 1},
+		{"arc backwards", Arc{v(0, 0), 5, math.Pi / 2, -math.Pi / 2, 0}, v(5, -1), 1},
+		{"arc wraps", Arc{v(0, 0), 5, -math.Pi / 4, math.Pi / 2, 0}, v(6, 0), 1},
+		{"full arc", Arc{v(0, 0), 5, 0, 2 * math.Pi, 0}, v(-6, 0), 1},
+	}
+
+	for _, tt := range tests {
+		if d := tt.shape.SignedDistance(tt.p); math.Abs(d-tt.expected) > 1e-9 && d != tt.expected {
+			t.Errorf("%v: distance at %v should be %v, not %v", tt.name, tt.p, tt.expected, d)
+		}
+	}
+}
+
+func TestEllipseMatchesSampledOutline(t *testing.T) {
+	e := Ellipse{v(2, 1), v(5, 2)}
+
+	// approximate the exact distance by densely sampling the outline
+	var outline []vector.Vec
+	for i := 0; i < 20000; i++ {
+		a := 2 * math.Pi * float64(i) / 20000
+		outline = append(outline, v(2+5*math.Cos(a), 1+2*math.Sin(a)))
+	}
+
+	for y := -4.0; y <= 6; y += 0.7 {
+		for x := -6.0; x <= 10; x += 0.9 {
+			p := v(x, y)
+			expected := math.Inf(1)
+			for _, q := range outline {
+				expected = math.Min(expected, p.Sub(q).Len())
+			}
+
+			if d := e.SignedDistance(p); math.Abs(math.Abs(d)-expected) > 1e-3 {
+				t.Errorf("Distance at %v should be about %v, not %v", p, expected, d)
+			}
+		}
+	}
+}
+
+func TestFunc(t *testing.T) {
+	var s Shape = Func(func(p vector.Vec) float64 { return p.X })
+
+	if d := s.SignedDistance(v(3, 4)); d != 3 {
+		t.Errorf("Func should return its result, 3, not %v", d)
+	}
+}
+
+func TestBake(t *testing.T) {
+	field := Bake(Circle{v(8, 7), 5}, 16, 14)
+
+	if field.Width != 16 || field.Height != 14 {
+		t.Fatalf("Field size should be (16, 14), not (%v, %v)", field.Width, field.Height)
+	}
+
+	for y := 0; y < 14; y++ {
+		for x := 0; x < 16; x++ {
+			expected := math.Hypot(float64(x)+0.5-8, float64(y)+0.5-7) - 5
+			if f := field.At(x, y); math.Abs(f-expected) > 1e-9 {
+				t.Errorf("Field value at (%v, %v) should be %v, not %v", x, y, expected, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package svg
+
+import (
+	"math"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// arcToCubics approximates an SVG elliptical arc with cubic Béziers, each spanning at most a
+// quarter turn. The conversion from endpoint to centre parameterisation follows appendix F.6
+// of the SVG specification, including the scaling up of radii that are too small.
+func arcToCubics(from vector.Vec, rx, ry, rotation float64, largeArc, sweep bool, to vector.Vec) []vector.Segment {
+	if from == to {
+		return nil
+	}
+
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []vector.Segment{vector.Line{P0: from, P1: to}}
+	}
+
+	phi := rotation * math.Pi / 180
+	sinPhi, cosPhi := math.Sincos(phi)
+
+	// rotate the midpoint between the end points into the ellipse's frame
+	dx, dy := (from.X-to.X)/2, (from.Y-to.Y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	// scale up radii that are too small to span the end points
+	if lambda := (x1*x1)/(rx*rx) + (y1*y1)/(ry*ry); lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx, ry = rx*s, ry*s
+	}
+
+	// find the centre of the ellipse
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+
+	cx := cosPhi*cx1 - sinPhi*cy1 + (from.X+to.X)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (from.Y+to.Y)/2
+
+	// find the start angle and the angle swept
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	// point maps an angle on the unit circle onto the ellipse
+	point := func(a float64) vector.Vec {
+		sin, cos := math.Sincos(a)
+		return vector.Vec{
+			X: cx + rx*cos*cosPhi - ry*sin*sinPhi,
+			Y: cy + rx*cos*sinPhi + ry*sin*cosPhi,
+		}
+	}
+	derivative := func(a float64) vector.Vec {
+		sin, cos := math.Sincos(a)
+		return vector.Vec{
+			X: -rx*sin*cosPhi - ry*cos*sinPhi,
+			Y: -rx*sin*sinPhi + ry*cos*cosPhi,
+		}
+	}
+
+	n := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	step := delta / float64(n)
+	k := 4.0 / 3 * math.Tan(step/4)
+
+	segs := make([]vector.Segment, n)
+	p0 := from
+	for i := 0; i < n; i++ {
+		a0 := theta + float64(i)*step
+		a1 := a0 + step
+
+		p3 := point(a1)
+		if i == n-1 {
+			// land exactly on the end point
+			p3 = to
+		}
+
+		segs[i] = vector.Cubic{
+			P0: p0,
+			P1: p0.Add(derivative(a0).Scale(k)),
+			P2: p3.Sub(derivative(a1).Scale(k)),
+			P3: p3,
+		}
+		p0 = p3
+	}
+
+	return segs
+}
@@ -0,0 +1,84 @@
+package svg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Encode writes the document as an SVG document of one <path> element per path, sized to its
+// view box. Line, quadratic and cubic segments are written as such, and each path keeps its fill
+// rule. Coordinates are rounded to 3 decimal places.
+func Encode(w io.Writer, doc *Document) error {
+	bw := bufio.NewWriter(w)
+	vb := doc.ViewBox
+
+	fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" viewBox=\"%v %v %v %v\">\n",
+		number(vb[2]), number(vb[3]), number(vb[0]), number(vb[1]), number(vb[2]), number(vb[3]))
+	for _, p := range doc.Paths {
+		rule := "nonzero"
+		if p.FillRule == vector.EvenOdd {
+			rule = "evenodd"
+		}
+		fmt.Fprintf(bw, "<path fill-rule=\"%v\" d=\"%v\"/>\n", rule, PathData(p))
+	}
+	fmt.Fprintf(bw, "</svg>\n")
+
+	return bw.Flush()
+}
+
+// PathData returns the shape as the path data of an SVG <path> element, with one closed subpath
+// per contour
+func PathData(s *vector.Shape) string {
+	var b strings.Builder
+	pt := func(v vector.Vec) string {
+		return number(v.X) + "," + number(v.Y)
+	}
+
+	for _, c := range s.Contours {
+		if len(c) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		start := c[0].Point(0)
+		b.WriteString("M" + pt(start))
+
+		for i, seg := range c {
+			switch seg := seg.(type) {
+			case vector.Line:
+				// closing the subpath draws the final line back to the start
+				if i == len(c)-1 && seg.P1 == start {
+					continue
+				}
+				b.WriteString(" L" + pt(seg.P1))
+			case vector.Quadratic:
+				b.WriteString(" Q" + pt(seg.P1) + " " + pt(seg.P2))
+			case vector.Cubic:
+				b.WriteString(" C" + pt(seg.P1) + " " + pt(seg.P2) + " " + pt(seg.P3))
+			default:
+				b.WriteString(" L" + pt(seg.Point(1)))
+			}
+		}
+		b.WriteString(" Z")
+	}
+
+	return b.String()
+}
+
+// number formats a coordinate to at most 3 decimal places
+func number(v float64) string {
+	v = math.Round(v*1000) / 1000
+	if v == 0 {
+		// avoid writing negative zero
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,60 @@
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+func TestEncode(t *testing.T) {
+	shape := &vector.Shape{
+		Contours: []vector.Contour{
+			{
+				vector.Line{P0: vector.Vec{X: 1, Y: 1}, P1: vector.Vec{X: 9, Y: 1}},
+				vector.Quadratic{P0: vector.Vec{X: 9, Y: 1}, P1: vector.Vec{X: 9, Y: 9}, P2: vector.Vec{X: 5, Y: 9}},
+				vector.Cubic{P0: vector.Vec{X: 5, Y: 9}, P1: vector.Vec{X: 3, Y: 9}, P2: vector.Vec{X: 1, Y: 7}, P3: vector.Vec{X: 1, Y: 1}},
+			},
+			{
+				vector.Line{P0: vector.Vec{X: 3, Y: 3}, P1: vector.Vec{X: 5.12345, Y: 3}},
+				vector.Line{P0: vector.Vec{X: 5.12345, Y: 3}, P1: vector.Vec{X: 5, Y: 5}},
+				vector.Line{P0: vector.Vec{X: 5, Y: 5}, P1: vector.Vec{X: 3, Y: 3}},
+			},
+		},
+		FillRule: vector.EvenOdd,
+	}
+
+	if d, expected := PathData(shape), "M1,1 L9,1 Q9,9 5,9 C3,9 1,7 1,1 Z M3,3 L5.123,3 L5,5 Z"; d != expected {
+		t.Errorf("Path data should be %q, not %q", expected, d)
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, &Document{ViewBox: [4]float64{0, 0, 10, 12}, Paths: []*vector.Shape{shape}}); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if !strings.Contains(buf.String(), `fill-rule="evenodd"`) {
+		t.Errorf("Path should have an evenodd fill rule, not be %v", buf.String())
+	}
+
+	// the encoded document decodes back to the same geometry
+	doc, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if doc.ViewBox != [4]float64{0, 0, 10, 12} {
+		t.Errorf("ViewBox should be [0 0 10 12], not %v", doc.ViewBox)
+	}
+	if len(doc.Paths) != 1 {
+		t.Fatalf("There should be 1 path, not %v", len(doc.Paths))
+	}
+	decoded := doc.Paths[0]
+	if decoded.FillRule != vector.EvenOdd {
+		t.Errorf("Fill rule should be EvenOdd")
+	}
+	for _, p := range []vector.Vec{{X: 2, Y: 2}, {X: 7, Y: 5}, {X: 4, Y: 3.5}, {X: 0.5, Y: 5}} {
+		if decoded.Contains(p) != shape.Contains(p) {
+			t.Errorf("Decoded shape should contain %v as the original does (%v)", p, shape.Contains(p))
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// ParsePath parses the path data of an SVG path element (its d attribute) into a Shape.
+// All path commands are supported, in both absolute and relative forms. Every subpath is
+// closed, as it would be when filled, and subpaths of lines that enclose no area are dropped.
+func ParsePath(d string, rule vector.FillRule) (*vector.Shape, error) {
+	p := &pathParser{s: d}
+	b := &pathBuilder{shape: &vector.Shape{FillRule: rule}}
+
+	var cmd byte
+	for {
+		p.skipSeparators()
+		if p.pos >= len(p.s) {
+			break
+		}
+
+		if c := p.s[p.pos]; isCommand(c) {
+			cmd = c
+			p.pos++
+		} else if cmd == 0 {
+			return nil, p.errorf("expected a command")
+		} else if cmd == 'Z' || cmd == 'z' {
+			return nil, p.errorf("unexpected arguments to a close path command")
+		}
+
+		if err := b.command(p, cmd); err != nil {
+			return nil, err
+		}
+
+		// subsequent coordinate pairs after a move are implicit line commands
+		switch cmd {
+		case 'M':
+			cmd = 'L'
+		case 'm':
+			cmd = 'l'
+		}
+	}
+
+	b.closeSubpath()
+	return b.shape, nil
+}
+
+func isCommand(c byte) bool {
+	switch c {
+	case 'M', 'm', 'L', 'l', 'H', 'h', 'V', 'v', 'C', 'c', 'S', 's', 'Q', 'q', 'T', 't', 'A', 'a', 'Z', 'z':
+		return true
+	}
+	return false
+}
+
+// pathBuilder accumulates the segments of each subpath into a shape
+type pathBuilder struct {
+	shape   *vector.Shape
+	contour vector.Contour
+	start   vector.Vec
+	current vector.Vec
+	// the last control point, which the smooth curve commands reflect
+	lastCtrl vector.Vec
+	lastCmd  byte
+}
+
+func (b *pathBuilder) command(p *pathParser, cmd byte) error {
+	rel := cmd >= 'a'
+	origin := vector.Vec{}
+	if rel {
+		origin = b.current
+	}
+
+	// pt parses a coordinate pair, relative to the current point for lowercase commands
+	pt := func() (vector.Vec, error) {
+		v, err := p.pair()
+		return v.Add(origin), err
+	}
+
+	upper := cmd &^ 0x20
+	switch upper {
+	case 'Z':
+		b.closeSubpath()
+		b.current = b.start
+
+	case 'M':
+		to, err := pt()
+		if err != nil {
+			return err
+		}
+		b.closeSubpath()
+		b.start, b.current = to, to
+
+	case 'L', 'H', 'V':
+		to := b.current
+		var err error
+		switch upper {
+		case 'L':
+			to, err = pt()
+		case 'H':
+			to.X, err = p.number()
+			to.X += origin.X
+		case 'V':
+			to.Y, err = p.number()
+			to.Y += origin.Y
+		}
+		if err != nil {
+			return err
+		}
+		b.add(vector.Line{P0: b.current, P1: to})
+
+	case 'C', 'S':
+		var c1 vector.Vec
+		if upper == 'S' {
+			c1 = b.reflectedCtrl('C', 'S')
+		} else {
+			var err error
+			if c1, err = pt(); err != nil {
+				return err
+			}
+		}
+		c2, err := pt()
+		if err != nil {
+			return err
+		}
+		to, err := pt()
+		if err != nil {
+			return err
+		}
+		b.add(vector.Cubic{P0: b.current, P1: c1, P2: c2, P3: to})
+		b.lastCtrl = c2
+
+	case 'Q', 'T':
+		var c vector.Vec
+		if upper == 'T' {
+			c = b.reflectedCtrl('Q', 'T')
+		} else {
+			var err error
+			if c, err = pt(); err != nil {
+				return err
+			}
+		}
+		to, err := pt()
+		if err != nil {
+			return err
+		}
+		b.add(vector.Quadratic{P0: b.current, P1: c, P2: to})
+		b.lastCtrl = c
+
+	case 'A':
+		rx, err := p.number()
+		if err != nil {
+			return err
+		}
+		ry, err := p.number()
+		if err != nil {
+			return err
+		}
+		rotation, err := p.number()
+		if err != nil {
+			return err
+		}
+		largeArc, err := p.flag()
+		if err != nil {
+			return err
+		}
+		sweep, err := p.flag()
+		if err != nil {
+			return err
+		}
+		to, err := pt()
+		if err != nil {
+			return err
+		}
+		for _, seg := range arcToCubics(b.current, rx, ry, rotation, largeArc, sweep, to) {
+			b.add(seg)
+		}
+	}
+
+	b.lastCmd = upper
+	return nil
+}
+
+// reflectedCtrl returns the first control point of a smooth curve, which is the reflection of
+// the previous curve's last control point if the previous command was one of the given ones
+func (b *pathBuilder) reflectedCtrl(cmds ...byte) vector.Vec {
+	for _, c := range cmds {
+		if b.lastCmd == c {
+			return b.current.Scale(2).Sub(b.lastCtrl)
+		}
+	}
+	return b.current
+}
+
+func (b *pathBuilder) add(seg vector.Segment) {
+	b.contour = append(b.contour, seg)
+	b.current = seg.Point(1)
+}
+
+// closeSubpath finishes the current subpath, joining it back to its start
+func (b *pathBuilder) closeSubpath() {
+	if len(b.contour) > 0 && b.current != b.start {
+		b.contour = append(b.contour, vector.Line{P0: b.current, P1: b.start})
+	}
+
+	if !enclosesNothing(b.contour) {
+		b.shape.Contours = append(b.shape.Contours, b.contour)
+	}
+	b.contour = nil
+}
+
+// enclosesNothing predicates whether a contour is made only of lines that all lie along a single
+// straight line, such that filling it paints nothing
+func enclosesNothing(c vector.Contour) bool {
+	var dir vector.Vec
+	for _, seg := range c {
+		if !isLine(seg) {
+			return false
+		}
+		v := seg.Point(1).Sub(c[0].Point(0))
+		if dir == (vector.Vec{}) {
+			dir = v
+		} else if math.Abs(dir.Cross(v)) > 1e-12*dir.Len()*v.Len() {
+			return false
+		}
+	}
+	return true
+}
+
+func isLine(s vector.Segment) bool {
+	_, ok := s.(vector.Line)
+	return ok
+}
+
+// pathParser tokenises path data
+type pathParser struct {
+	s   string
+	pos int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("svg path data at offset %v: %v", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pathParser) skipSeparators() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\r', '\n', '\f', ',':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// number parses a number according to the SVG grammar, where numbers need not be separated
+// when unambiguous, e.g. "1-2.5.5" is the three numbers 1, -2.5 and 0.5
+func (p *pathParser) number() (float64, error) {
+	p.skipSeparators()
+	start := p.pos
+
+	if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+		p.pos++
+	}
+	p.digits()
+	if p.pos < len(p.s) && p.s[p.pos] == '.' {
+		p.pos++
+		p.digits()
+	}
+	if p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+		mark := p.pos
+		p.pos++
+		if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+			p.pos++
+		}
+		if !p.digits() {
+			p.pos = mark
+		}
+	}
+
+	v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("expected a number")
+	}
+	return v, nil
+}
+
+func (p *pathParser) digits() bool {
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *pathParser) pair() (vector.Vec, error) {
+	x, err := p.number()
+	if err != nil {
+		return vector.Vec{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return vector.Vec{}, err
+	}
+	return vector.Vec{X: x, Y: y}, nil
+}
+
+// flag parses an arc flag, which is a single 0 or 1 that need not be separated from what follows
+func (p *pathParser) flag() (bool, error) {
+	p.skipSeparators()
+	if p.pos < len(p.s) && (p.s[p.pos] == '0' || p.s[p.pos] == '1') {
+		p.pos++
+		return p.s[p.pos-1] == '1', nil
+	}
+	return false, p.errorf("expected a flag")
+}
@@ -0,0 +1,134 @@
+package svg
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+func v(x, y float64) vector.Vec {
+	return vector.Vec{X: x, Y: y}
+}
+
+func TestParsePath(t *testing.T) {
+	shape, err := ParsePath("M10,10 L20 10 h5v5 H10z m2,2 l1-1.5.5.5 Z", vector.EvenOdd)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	expected := &vector.Shape{
+		FillRule: vector.EvenOdd,
+		Contours: []vector.Contour{
+			{
+				vector.Line{P0: v(10, 10), P1: v(20, 10)},
+				vector.Line{P0: v(20, 10), P1: v(25, 10)},
+				vector.Line{P0: v(25, 10), P1: v(25, 15)},
+				vector.Line{P0: v(25, 15), P1: v(10, 15)},
+				vector.Line{P0: v(10, 15), P1: v(10, 10)},
+			},
+			{
+				vector.Line{P0: v(12, 12), P1: v(13, 10.5)},
+				vector.Line{P0: v(13, 10.5), P1: v(13.5, 11)},
+				vector.Line{P0: v(13.5, 11), P1: v(12, 12)},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(shape, expected) {
+		t.Errorf("Parsed shape should be %#v, not %#v", expected, shape)
+	}
+}
+
+func TestParsePathDropsEmptySubpaths(t *testing.T) {
+	for _, d := range []string{"M0 0 L10 0", "M0 0 L10 0 L20 0 Z", "M0 0 L10 0 L0 0 Z", "M0 0 L1 1.5 L2 3 L0.5 0.75 Z", "M5 5 Z"} {
+		shape, err := ParsePath(d+" M0 0 h1 v1 z", vector.NonZero)
+		if err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		if len(shape.Contours) != 1 {
+			t.Errorf("Subpath %q encloses no area so should be dropped, leaving 1 contour, not %v", d, len(shape.Contours))
+		}
+	}
+
+	// a bow-tie has no net area but still encloses some, and a curve back and forth may bulge
+	for _, d := range []string{"M0 0 L10 10 L10 0 L0 10 Z", "M0 0 Q5 5 10 0 Z"} {
+		shape, err := ParsePath(d, vector.NonZero)
+		if err != nil {
+			t.Fatalf("Error should have been nil, not %v", err)
+		}
+		if len(shape.Contours) != 1 {
+			t.Errorf("Subpath %q should be kept, not dropped", d)
+		}
+	}
+}
+
+func TestParsePathCurves(t *testing.T) {
+	shape, err := ParsePath("M0 0 C0 10 10 10 10 0 S20-10 20 0 Q25 5 30 0 T40 0 z", vector.NonZero)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	expected := vector.Contour{
+		vector.Cubic{P0: v(0, 0), P1: v(0, 10), P2: v(10, 10), P3: v(10, 0)},
+		vector.Cubic{P0: v(10, 0), P1: v(10, -10), P2: v(20, -10), P3: v(20, 0)},
+		vector.Quadratic{P0: v(20, 0), P1: v(25, 5), P2: v(30, 0)},
+		vector.Quadratic{P0: v(30, 0), P1: v(35, -5), P2: v(40, 0)},
+		vector.Line{P0: v(40, 0), P1: v(0, 0)},
+	}
+
+	if len(shape.Contours) != 1 || !reflect.DeepEqual(shape.Contours[0], expected) {
+		t.Errorf("Parsed contour should be %#v, not %#v", expected, shape.Contours)
+	}
+}
+
+func TestParsePathArc(t *testing.T) {
+	// two half circle arcs of radius 5 centred on (10, 10), written with compact flags
+	shape, err := ParsePath("M15,10 A5,5 0 1 1 5,10 a5 5 0 1110 0z", vector.NonZero)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	for _, c := range shape.Contours {
+		for _, seg := range c {
+			for i := 0; i <= 10; i++ {
+				p := seg.Point(float64(i) / 10)
+				if r := p.Sub(v(10, 10)).Len(); math.Abs(r-5) > 0.01 {
+					t.Errorf("Arc point %v should be 5 from the centre, not %v", p, r)
+				}
+			}
+		}
+	}
+
+	if !shape.Contains(v(10, 10)) || shape.Contains(v(16, 10)) {
+		t.Errorf("Arcs should enclose the circle")
+	}
+}
+
+func TestParsePathArcRadiiTooSmall(t *testing.T) {
+	// radii too small to reach are scaled up to a half ellipse
+	shape, err := ParsePath("M0,0 A1,1 0 0 1 10,0 z", vector.NonZero)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	mid := shape.Contours[0][len(shape.Contours[0])/2-1].Point(1)
+	if mid.Sub(v(5, -5)).Len() > 1e-9 && mid.Sub(v(5, 5)).Len() > 1e-9 {
+		t.Errorf("Mid point of the scaled arc should lie at a radius of 5, not at %v", mid)
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, d := range []string{
+		"10,10 L 20,20",
+		"M 10",
+		"M 10,10 L 20,x",
+		"M 0,0 A 1,1 0 2 0 5,5",
+		"M 0,0 L 1,1 Z 5",
+	} {
+		if _, err := ParsePath(d, vector.NonZero); err == nil {
+			t.Errorf("Parsing %q should return an error", d)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Package svg reads vector shapes from SVG documents so that Signed-Distance-Fields
+// can be computed from them exactly, at any resolution.
+package svg
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// Document is the filled geometry of an SVG document
+type Document struct {
+	// ViewBox is the area of user space visible in the document as (min-x, min-y, width, height)
+	ViewBox [4]float64
+	// Paths are the filled shapes of the document in user space, in document order
+	Paths []*vector.Shape
+}
+
+// Decode reads an SVG document, collecting the geometry of every filled path, rect, circle,
+// ellipse, polygon and polyline element. Transforms and fill rules are honoured, including those
+// inherited from groups, but styling such as strokes, clipping and masking is ignored.
+func Decode(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	hasViewBox := false
+
+	type state struct {
+		transform matrix
+		fillRule  vector.FillRule
+		filled    bool
+		hidden    bool
+	}
+	stack := []state{{identity, vector.NonZero, true, false}}
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch el := tok.(type) {
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case xml.StartElement:
+			attrs := attributes(el)
+			st := stack[len(stack)-1]
+
+			if t, ok := attrs["transform"]; ok {
+				m, err := parseTransform(t)
+				if err != nil {
+					return nil, err
+				}
+				st.transform = st.transform.mul(m)
+			}
+			switch attrs["fill-rule"] {
+			case "evenodd":
+				st.fillRule = vector.EvenOdd
+			case "nonzero":
+				st.fillRule = vector.NonZero
+			}
+			switch attrs["fill"] {
+			case "none", "transparent":
+				st.filled = false
+			case "":
+			default:
+				st.filled = true
+			}
+
+			switch el.Name.Local {
+			case "svg":
+				if len(stack) == 1 {
+					hasViewBox, err = doc.readSize(attrs)
+					if err != nil {
+						return nil, err
+					}
+				}
+			case "defs", "clipPath", "mask", "symbol", "marker", "pattern":
+				// these are not rendered where they are defined
+				st.hidden = true
+			}
+
+			if !st.hidden && st.filled {
+				if d, ok := shapePathData(el.Name.Local, attrs); ok {
+					shape, err := ParsePath(d, st.fillRule)
+					if err != nil {
+						return nil, err
+					}
+					if len(shape.Contours) > 0 {
+						doc.Paths = append(doc.Paths, shape.Transform(st.transform.apply))
+					}
+				}
+			}
+
+			stack = append(stack, st)
+		}
+	}
+
+	if !hasViewBox {
+		// without a declared size the view box fits the geometry
+		min, max := doc.Shape().Bounds()
+		doc.ViewBox = [4]float64{min.X, min.Y, max.X - min.X, max.Y - min.Y}
+	}
+	if !(doc.ViewBox[2] > 0 && doc.ViewBox[3] > 0) {
+		return nil, errors.New("svg has an empty view box and no geometry to size it by")
+	}
+
+	return doc, nil
+}
+
+// readSize reads the view box of the document from the attributes of the root svg element,
+// returning whether one was found
+func (doc *Document) readSize(attrs map[string]string) (bool, error) {
+	if vb, ok := attrs["viewBox"]; ok {
+		p := &pathParser{s: vb}
+		for i := range doc.ViewBox {
+			v, err := p.number()
+			if err != nil {
+				return false, fmt.Errorf("invalid svg viewBox %q", vb)
+			}
+			doc.ViewBox[i] = v
+		}
+		return true, nil
+	}
+
+	w, wErr := length(attrs["width"])
+	h, hErr := length(attrs["height"])
+	if wErr != nil || hErr != nil {
+		return false, nil
+	}
+	doc.ViewBox = [4]float64{0, 0, w, h}
+	return true, nil
+}
+
+// attributes returns the attributes of an element, including those set within its style attribute
+func attributes(el xml.StartElement) map[string]string {
+	attrs := map[string]string{}
+	for _, a := range el.Attr {
+		attrs[a.Name.Local] = strings.TrimSpace(a.Value)
+	}
+
+	for _, decl := range strings.Split(attrs["style"], ";") {
+		if kv := strings.SplitN(decl, ":", 2); len(kv) == 2 {
+			attrs[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	return attrs
+}
+
+// length parses an SVG length, ignoring any px units
+func length(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(s, "px"), 64)
+}
+
+// shapePathData returns the equivalent path data for a shape element
+func shapePathData(name string, attrs map[string]string) (string, bool) {
+	num := func(key string) float64 {
+		v, _ := length(attrs[key])
+		return v
+	}
+
+	switch name {
+	case "path":
+		return attrs["d"], true
+
+	case "rect":
+		x, y, w, h := num("x"), num("y"), num("width"), num("height")
+		rx, ry := num("rx"), num("ry")
+		if _, ok := attrs["ry"]; !ok {
+			ry = rx
+		}
+		if _, ok := attrs["rx"]; !ok {
+			rx = ry
+		}
+		rx, ry = math.Min(rx, w/2), math.Min(ry, h/2)
+		if rx <= 0 || ry <= 0 {
+			return fmt.Sprintf("M%v,%v h%v v%v h%v z", x, y, w, h, -w), true
+		}
+		return fmt.Sprintf("M%v,%v h%v a%v,%v 0 0 1 %v,%v v%v a%v,%v 0 0 1 %v,%v h%v a%v,%v 0 0 1 %v,%v v%v a%v,%v 0 0 1 %v,%v z",
+			x+rx, y, w-2*rx, rx, ry, rx, ry, h-2*ry, rx, ry, -rx, ry, -(w - 2*rx), rx, ry, -rx, -ry, -(h - 2*ry), rx, ry, rx, -ry), true
+
+	case "circle", "ellipse":
+		cx, cy := num("cx"), num("cy")
+		rx, ry := num("rx"), num("ry")
+		if name == "circle" {
+			rx, ry = num("r"), num("r")
+		}
+		return fmt.Sprintf("M%v,%v A%v,%v 0 1 1 %v,%v A%v,%v 0 1 1 %v,%v z",
+			cx+rx, cy, rx, ry, cx-rx, cy, rx, ry, cx+rx, cy), true
+
+	case "polygon", "polyline":
+		// a polyline is closed when filled so is the same as a polygon
+		return "M" + attrs["points"] + "z", true
+	}
+
+	return "", false
+}
+
+// Shape returns the union of the paths of the document, each filled according to its own fill rule
+func (doc *Document) Shape() vector.Union {
+	return append(vector.Union{}, doc.Paths...)
+}
+
+// Fit returns the Shape of the document mapped from its view box into pixels for an image of
+// size w*h, preserving the aspect ratio and centring it as SVG does by default
+func (doc *Document) Fit(w, h int) vector.Union {
+	vb := doc.ViewBox
+	scale := math.Min(float64(w)/vb[2], float64(h)/vb[3])
+	offset := vector.Vec{
+		X: (float64(w)-vb[2]*scale)/2 - vb[0]*scale,
+		Y: (float64(h)-vb[3]*scale)/2 - vb[1]*scale,
+	}
+
+	return doc.Shape().Transform(func(v vector.Vec) vector.Vec {
+		return v.Scale(scale).Add(offset)
+	})
+}
@@ -0,0 +1,166 @@
+package svg
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+const testSVG = `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="100px" height="50px" viewBox="0 0 20 10">
+	<defs><path d="M0,0 h1 v1 z"/></defs>
+	<g transform="translate(10, 0)" style="fill-rule: evenodd">
+		<path d="M0,0 h10 v10 h-10 z M2,2 h6 v6 h-6 z"/>
+	</g>
+	<rect x="1" y="1" width="4" height="3" transform="scale(2, 1)"/>
+	<circle cx="5" cy="8" r="1"/>
+	<path d="M0,0 h1 v1 z" fill="none"/>
+</svg>`
+
+func TestDecode(t *testing.T) {
+	doc, err := Decode(strings.NewReader(testSVG))
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	if doc.ViewBox != [4]float64{0, 0, 20, 10} {
+		t.Errorf("ViewBox should be [0 0 20 10], not %v", doc.ViewBox)
+	}
+
+	if len(doc.Paths) != 3 {
+		t.Fatalf("Only the 3 filled and rendered shapes should be decoded, not %v", len(doc.Paths))
+	}
+
+	group := doc.Paths[0]
+	if group.FillRule != vector.EvenOdd {
+		t.Errorf("Fill rule should be inherited from the group as EvenOdd")
+	}
+	if !group.Contains(vector.Vec{X: 11, Y: 5}) || group.Contains(vector.Vec{X: 15, Y: 5}) {
+		t.Errorf("Translated square with an even-odd hole should contain (11, 5) but not (15, 5)")
+	}
+
+	rect := doc.Paths[1]
+	if min, max := rect.Bounds(); min != (vector.Vec{X: 2, Y: 1}) || max != (vector.Vec{X: 10, Y: 4}) {
+		t.Errorf("Scaled rect should span (2, 1)-(10, 4), not %v-%v", min, max)
+	}
+
+	circle := doc.Paths[2]
+	if d := circle.SignedDistance(vector.Vec{X: 5, Y: 8}); math.Abs(d+1) > 1e-3 {
+		t.Errorf("Circle centre should be 1 inside its edge, not %v", d)
+	}
+}
+
+func TestDecodeWithoutViewBox(t *testing.T) {
+	doc, err := Decode(strings.NewReader(`<svg><path d="M2,3 h4 v5 h-4 z"/></svg>`))
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	if doc.ViewBox != [4]float64{2, 3, 4, 5} {
+		t.Errorf("ViewBox should fit the geometry as [2 3 4 5], not %v", doc.ViewBox)
+	}
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	for _, doc := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+		`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0 L10 0"/></svg>`,
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 0 10"><path d="M0 0 h1 v1 z"/></svg>`,
+	} {
+		if _, err := Decode(strings.NewReader(doc)); err == nil {
+			t.Errorf("Decoding %v should fail as it has no size", doc)
+		}
+	}
+}
+
+func TestDocumentShape(t *testing.T) {
+	doc, err := Decode(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg">
+		<path d="M0 0 H10 V10 H0 Z"/>
+		<path d="M5 5 V15 H15 V5 Z"/>
+		<path fill-rule="evenodd" d="M20 0 h10 v10 h-10 z M22 2 h6 v6 h-6 z"/>
+	</svg>`))
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	// paths of opposite winding overlap without cancelling, and each keeps its own fill rule
+	shape := doc.Shape()
+	for _, test := range []struct {
+		p      vector.Vec
+		inside bool
+	}{
+		{v(7, 7), true},
+		{v(2, 2), true},
+		{v(12, 12), true},
+		{v(12, 2), false},
+		{v(21, 1), true},
+		{v(25, 5), false},
+	} {
+		if shape.Contains(test.p) != test.inside {
+			t.Errorf("Shape should contain %v: %v", test.p, test.inside)
+		}
+		if d := shape.SignedDistance(test.p); (d < 0) != test.inside {
+			t.Errorf("Signed distance at %v should be negative only inside, not %v", test.p, d)
+		}
+	}
+	if d := shape.SignedDistance(v(7, 7)); d != -3 {
+		t.Errorf("Signed distance at (7, 7) should be -3 as the least of either path, not %v", d)
+	}
+
+	// merging the paths into one set of contours covers the same area
+	merged := shape.Merge()
+	for _, p := range []vector.Vec{v(7, 7), v(2, 2), v(12, 12), v(12, 2), v(21, 1), v(25, 5)} {
+		if merged.Contains(p) != shape.Contains(p) {
+			t.Errorf("Merged shape should contain %v as the union does", p)
+		}
+	}
+}
+
+func TestDocumentFit(t *testing.T) {
+	doc := &Document{
+		ViewBox: [4]float64{10, 10, 20, 10},
+		Paths: []*vector.Shape{{Contours: []vector.Contour{{
+			vector.Line{P0: v(10, 10), P1: v(30, 10)},
+			vector.Line{P0: v(30, 10), P1: v(30, 20)},
+			vector.Line{P0: v(30, 20), P1: v(10, 10)},
+		}}}},
+	}
+
+	// the view box is twice as wide as it is tall so is centred vertically
+	min, max := doc.Fit(40, 40).Bounds()
+	if min != v(0, 10) || max != v(40, 30) {
+		t.Errorf("Fitted shape should span (0, 10)-(40, 30), not %v-%v", min, max)
+	}
+}
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		s   string
+		in  vector.Vec
+		out vector.Vec
+	}{
+		{"translate(1 2)", v(1, 1), v(2, 3)},
+		{"scale(2)", v(1, 3), v(2, 6)},
+		{"rotate(90)", v(1, 0), v(0, 1)},
+		{"rotate(90, 1, 1)", v(2, 1), v(1, 2)},
+		{"translate(10,0) scale(2,3)", v(1, 1), v(12, 3)},
+		{"matrix(1 0 0 1 5 6)", v(0, 0), v(5, 6)},
+		{"skewX(45)", v(0, 1), v(1, 1)},
+	}
+
+	for _, tt := range tests {
+		m, err := parseTransform(tt.s)
+		if err != nil {
+			t.Fatalf("%v: error should have been nil, not %v", tt.s, err)
+		}
+		if got := m.apply(tt.in); got.Sub(tt.out).Len() > 1e-9 {
+			t.Errorf("%v should map %v to %v, not %v", tt.s, tt.in, tt.out, got)
+		}
+	}
+
+	if _, err := parseTransform("spin(45)"); err == nil {
+		t.Errorf("Unknown transform functions should return an error")
+	}
+}
@@ -0,0 +1,101 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// matrix is an affine transform (a, b, c, d, e, f) as used by the SVG transform attribute,
+// mapping (x, y) to (a*x + c*y + e, b*x + d*y + f)
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul returns the transform applying n first and then m
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m matrix) apply(v vector.Vec) vector.Vec {
+	return vector.Vec{
+		X: m[0]*v.X + m[2]*v.Y + m[4],
+		Y: m[1]*v.X + m[3]*v.Y + m[5],
+	}
+}
+
+// parseTransform parses the value of an SVG transform attribute
+func parseTransform(s string) (matrix, error) {
+	m := identity
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimLeft(s, " \t\r\n,") {
+		open := strings.IndexByte(s, '(')
+		end := strings.IndexByte(s, ')')
+		if open < 0 || end < open {
+			return identity, fmt.Errorf("invalid svg transform %q", s)
+		}
+
+		name := strings.TrimSpace(s[:open])
+		p := &pathParser{s: s[open+1 : end]}
+		args := []float64{}
+		for p.skipSeparators(); p.pos < len(p.s); p.skipSeparators() {
+			v, err := p.number()
+			if err != nil {
+				return identity, fmt.Errorf("invalid svg transform %q: %v", s, err)
+			}
+			args = append(args, v)
+		}
+		s = s[end+1:]
+
+		t, err := transformFunction(name, args)
+		if err != nil {
+			return identity, err
+		}
+		m = m.mul(t)
+	}
+
+	return m, nil
+}
+
+func transformFunction(name string, args []float64) (matrix, error) {
+	arg := func(i int, def float64) float64 {
+		if i < len(args) {
+			return args[i]
+		}
+		return def
+	}
+
+	switch {
+	case name == "matrix" && len(args) == 6:
+		return matrix{args[0], args[1], args[2], args[3], args[4], args[5]}, nil
+
+	case name == "translate" && (len(args) == 1 || len(args) == 2):
+		return matrix{1, 0, 0, 1, args[0], arg(1, 0)}, nil
+
+	case name == "scale" && (len(args) == 1 || len(args) == 2):
+		return matrix{args[0], 0, 0, arg(1, args[0]), 0, 0}, nil
+
+	case name == "rotate" && (len(args) == 1 || len(args) == 3):
+		sin, cos := math.Sincos(args[0] * math.Pi / 180)
+		cx, cy := arg(1, 0), arg(2, 0)
+		rotate := matrix{cos, sin, -sin, cos, 0, 0}
+		return matrix{1, 0, 0, 1, cx, cy}.mul(rotate).mul(matrix{1, 0, 0, 1, -cx, -cy}), nil
+
+	case name == "skewX" && len(args) == 1:
+		return matrix{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}, nil
+
+	case name == "skewY" && len(args) == 1:
+		return matrix{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}, nil
+	}
+
+	return identity, fmt.Errorf("invalid svg transform function %v with %v arguments", name, len(args))
+}
@@ -0,0 +1,232 @@
+package ttf
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/daveagill/go-sdf/sdf"
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// AtlasOptions configures how glyphs are rendered and packed into an atlas
+type AtlasOptions struct {
+	// Size is the font size in pixels per em
+	Size float64
+	// Padding is the number of pixels of field kept around each glyph's outline
+	Padding int
+	// Spread is the distance in pixels either side of the edge encoded by the atlas image
+	Spread float64
+	// Width is the width of the atlas in pixels. Its height grows to fit the glyphs.
+	Width int
+	// Runes are the characters to include. Characters the font has no glyph for are skipped.
+	Runes []rune
+}
+
+// AtlasGlyph locates a glyph in the atlas and holds its metrics in pixels
+type AtlasGlyph struct {
+	Rune  rune       `json:"rune"`
+	Index GlyphIndex `json:"index"`
+	// X, Y, Width and Height are the glyph's rectangle in the atlas, including its padding
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// BearingX is the offset from the pen position to the left of the rectangle
+	BearingX float64 `json:"bearingX"`
+	// BearingY is the height of the top of the rectangle above the baseline
+	BearingY float64 `json:"bearingY"`
+	// Advance is how far the pen moves after drawing the glyph
+	Advance float64 `json:"advance"`
+	// UV is the glyph's rectangle normalised to the atlas size as (u0, v0, u1, v1)
+	UV [4]float64 `json:"uv"`
+}
+
+// AtlasKerning adjusts the advance between a pair of characters, in pixels
+type AtlasKerning struct {
+	First  rune    `json:"first"`
+	Second rune    `json:"second"`
+	Amount float64 `json:"amount"`
+}
+
+// Atlas is a set of glyphs rendered into a single Signed-Distance-Field
+type Atlas struct {
+	// Field holds the signed distance of every glyph. Texels not covered by a glyph are set to
+	// Spread, i.e. fully outside.
+	Field   *sdf.SDF
+	Size    float64
+	Spread  float64
+	Padding int
+	// LineHeight is the distance between consecutive baselines
+	LineHeight float64
+	// Ascent is the distance from the top of a line to its baseline
+	Ascent  float64
+	Glyphs  []AtlasGlyph
+	Kerning []AtlasKerning
+}
+
+// NewAtlas renders the requested characters of a font into an atlas. Each glyph's field is
+// measured exactly to its outline and glyphs are packed into rows, tallest first.
+func NewAtlas(f *Font, opts AtlasOptions) (*Atlas, error) {
+	if opts.Size <= 0 {
+		return nil, errors.New("atlas font size must be positive")
+	}
+	if opts.Spread <= 0 {
+		return nil, errors.New("atlas spread must be positive")
+	}
+	if opts.Padding < 0 {
+		return nil, errors.New("atlas padding must not be negative")
+	}
+	if opts.Width <= 0 {
+		return nil, errors.New("atlas width must be positive")
+	}
+
+	scale := opts.Size / float64(f.UnitsPerEm)
+	a := &Atlas{
+		Size:       opts.Size,
+		Spread:     opts.Spread,
+		Padding:    opts.Padding,
+		LineHeight: float64(f.Ascent-f.Descent+f.LineGap) * scale,
+		Ascent:     float64(f.Ascent) * scale,
+	}
+
+	type rendered struct {
+		glyph AtlasGlyph
+		shape *vector.Shape
+	}
+	var glyphs []*rendered
+	seen := make(map[rune]bool)
+	glyphRunes := make(map[GlyphIndex][]rune)
+
+	for _, r := range opts.Runes {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+
+		index := f.Index(r)
+		if index == 0 {
+			continue
+		}
+		glyphRunes[index] = append(glyphRunes[index], r)
+
+		outline, err := f.Glyph(index)
+		if err != nil {
+			return nil, fmt.Errorf("glyph for %q: %v", r, err)
+		}
+
+		g := &rendered{glyph: AtlasGlyph{
+			Rune:    r,
+			Index:   index,
+			Advance: float64(f.HMetric(index).AdvanceWidth) * scale,
+		}}
+		glyphs = append(glyphs, g)
+
+		if len(outline.Contours) == 0 {
+			continue
+		}
+
+		// snap the padded bounds outwards to whole pixels so the outline keeps its sub-pixel position
+		min, max := outline.Bounds()
+		left := math.Floor(min.X*scale) - float64(opts.Padding)
+		right := math.Ceil(max.X*scale) + float64(opts.Padding)
+		top := math.Ceil(max.Y*scale) + float64(opts.Padding)
+		bottom := math.Floor(min.Y*scale) - float64(opts.Padding)
+
+		g.glyph.BearingX, g.glyph.BearingY = left, top
+		g.glyph.Width, g.glyph.Height = int(right-left), int(top-bottom)
+		if g.glyph.Width > opts.Width {
+			return nil, fmt.Errorf("glyph for %q is %v pixels wide which does not fit the atlas width", r, g.glyph.Width)
+		}
+
+		// flip from font units with y up to pixels with y down
+		g.shape = outline.Transform(func(v vector.Vec) vector.Vec {
+			return vector.Vec{X: v.X*scale - left, Y: top - v.Y*scale}
+		})
+	}
+
+	// pack into rows, tallest first so each row wastes little height
+	order := make([]*rendered, len(glyphs))
+	copy(order, glyphs)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].glyph.Height > order[j].glyph.Height
+	})
+
+	x, y, rowHeight := 0, 0, 0
+	for _, g := range order {
+		if g.shape == nil {
+			continue
+		}
+		if x+g.glyph.Width > opts.Width {
+			x, y, rowHeight = 0, y+rowHeight, 0
+		}
+		g.glyph.X, g.glyph.Y = x, y
+		x += g.glyph.Width
+		if g.glyph.Height > rowHeight {
+			rowHeight = g.glyph.Height
+		}
+	}
+	height := y + rowHeight
+
+	a.Field = sdf.New(opts.Width, height)
+	for i := range a.Field.Field {
+		a.Field.Field[i] = opts.Spread
+	}
+
+	for _, g := range glyphs {
+		if g.shape != nil {
+			field := g.shape.Field(g.glyph.Width, g.glyph.Height)
+			for gy := 0; gy < field.Height; gy++ {
+				for gx := 0; gx < field.Width; gx++ {
+					a.Field.Set(g.glyph.X+gx, g.glyph.Y+gy, field.At(gx, gy))
+				}
+			}
+
+			w, h := float64(opts.Width), float64(height)
+			g.glyph.UV = [4]float64{
+				float64(g.glyph.X) / w,
+				float64(g.glyph.Y) / h,
+				float64(g.glyph.X+g.glyph.Width) / w,
+				float64(g.glyph.Y+g.glyph.Height) / h,
+			}
+		}
+		a.Glyphs = append(a.Glyphs, g.glyph)
+	}
+
+	for pair, amount := range f.kern {
+		for _, first := range glyphRunes[GlyphIndex(pair>>16)] {
+			for _, second := range glyphRunes[GlyphIndex(pair&0xffff)] {
+				if amount != 0 {
+					a.Kerning = append(a.Kerning, AtlasKerning{first, second, float64(amount) * scale})
+				}
+			}
+		}
+	}
+	sort.Slice(a.Kerning, func(i, j int) bool {
+		ki, kj := a.Kerning[i], a.Kerning[j]
+		if ki.First != kj.First {
+			return ki.First < kj.First
+		}
+		return ki.Second < kj.Second
+	})
+
+	return a, nil
+}
+
+// Image encodes the atlas field as 8-bit gray, mapping distances in [-Spread, Spread] to
+// [255, 0] so that the inside of glyphs is bright and the edge lies at 50% gray
+func (a *Atlas) Image() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, a.Field.Width, a.Field.Height))
+
+	for y := 0; y < a.Field.Height; y++ {
+		for x := 0; x < a.Field.Width; x++ {
+			v := 0.5 - a.Field.At(x, y)/(2*a.Spread)
+			img.SetGray(x, y, color.Gray{uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))})
+		}
+	}
+
+	return img
+}
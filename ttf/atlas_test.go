@@ -0,0 +1,171 @@
+package ttf
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestAtlas(t *testing.T) *Atlas {
+	f := parseTestFont(t)
+
+	a, err := NewAtlas(f, AtlasOptions{Size: 20, Padding: 2, Spread: 2, Width: 32, Runes: []rune("AOB A?")})
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	return a
+}
+
+func findGlyph(a *Atlas, r rune) *AtlasGlyph {
+	for i := range a.Glyphs {
+		if a.Glyphs[i].Rune == r {
+			return &a.Glyphs[i]
+		}
+	}
+	return nil
+}
+
+func TestNewAtlas(t *testing.T) {
+	a := newTestAtlas(t)
+
+	if len(a.Glyphs) != 4 {
+		t.Fatalf("Atlas should skip duplicate and missing characters leaving 4 glyphs, not %v", len(a.Glyphs))
+	}
+	if a.LineHeight != 22 || a.Ascent != 16 {
+		t.Errorf("Line height and ascent should be 22 and 16, not %v and %v", a.LineHeight, a.Ascent)
+	}
+
+	g := findGlyph(a, 'A')
+	if g.Width != 14 || g.Height != 14 || g.BearingX != -2 || g.BearingY != 12 || g.Advance != 11 {
+		t.Errorf("Glyph 'A' metrics are wrong: %+v", *g)
+	}
+
+	// the glyph's field is measured to its outline, with the hole outside
+	if d := a.Field.At(g.X+3, g.Y+7); d != -1 {
+		t.Errorf("Distance within the glyph's stroke should be -1, not %v", d)
+	}
+	if d := a.Field.At(g.X+7, g.Y+7); d != 2 {
+		t.Errorf("Distance at the centre of the glyph's hole should be 2, not %v", d)
+	}
+
+	space := findGlyph(a, ' ')
+	if space.Width != 0 || space.Height != 0 || space.Advance != 12 {
+		t.Errorf("Space should have an empty rectangle and an advance of 12: %+v", *space)
+	}
+
+	if len(a.Kerning) != 1 || a.Kerning[0] != (AtlasKerning{'A', 'O', -1}) {
+		t.Errorf("Kerning should only hold A-O of -1, not %v", a.Kerning)
+	}
+}
+
+func TestNewAtlasPacking(t *testing.T) {
+	a := newTestAtlas(t)
+
+	bounds := image.Rect(0, 0, a.Field.Width, a.Field.Height)
+	var rects []image.Rectangle
+	for _, g := range a.Glyphs {
+		if g.Width == 0 {
+			continue
+		}
+		r := image.Rect(g.X, g.Y, g.X+g.Width, g.Y+g.Height)
+		if !r.In(bounds) {
+			t.Errorf("Glyph %q at %v lies outside the atlas %v", g.Rune, r, bounds)
+		}
+		for _, other := range rects {
+			if r.Overlaps(other) {
+				t.Errorf("Glyph %q at %v overlaps another at %v", g.Rune, r, other)
+			}
+		}
+		rects = append(rects, r)
+
+		uv := [4]float64{
+			float64(r.Min.X) / float64(bounds.Dx()), float64(r.Min.Y) / float64(bounds.Dy()),
+			float64(r.Max.X) / float64(bounds.Dx()), float64(r.Max.Y) / float64(bounds.Dy()),
+		}
+		if g.UV != uv {
+			t.Errorf("Glyph %q UV should be %v, not %v", g.Rune, uv, g.UV)
+		}
+	}
+}
+
+func TestNewAtlasErrors(t *testing.T) {
+	f := parseTestFont(t)
+
+	for _, opts := range []AtlasOptions{
+		{Size: 0, Spread: 2, Width: 32},
+		{Size: 20, Spread: 0, Width: 32},
+		{Size: 20, Spread: 2, Width: 0},
+		{Size: 20, Spread: 2, Padding: -1, Width: 32},
+		{Size: 20, Spread: 2, Width: 8, Runes: []rune("A")},
+	} {
+		if _, err := NewAtlas(f, opts); err == nil {
+			t.Errorf("Options %+v should return an error", opts)
+		}
+	}
+}
+
+func TestAtlasImage(t *testing.T) {
+	a := newTestAtlas(t)
+	img := a.Image()
+
+	g := findGlyph(a, 'A')
+	if v := img.GrayAt(g.X+3, g.Y+7).Y; v != 191 {
+		t.Errorf("Inside of the glyph should be encoded as 191, not %v", v)
+	}
+	if v := img.GrayAt(g.X+7, g.Y+7).Y; v != 0 {
+		t.Errorf("Distances of a full spread outside should be encoded as 0, not %v", v)
+	}
+}
+
+func TestAtlasWriteJSON(t *testing.T) {
+	a := newTestAtlas(t)
+
+	var buf bytes.Buffer
+	if err := a.WriteJSON(&buf); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	var doc struct {
+		Width   int
+		Height  int
+		Glyphs  []AtlasGlyph
+		Kerning []AtlasKerning
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("JSON should be valid, not %v", err)
+	}
+	if doc.Width != a.Field.Width || doc.Height != a.Field.Height || len(doc.Glyphs) != 4 || len(doc.Kerning) != 1 {
+		t.Errorf("JSON does not match the atlas: %+v", doc)
+	}
+	if doc.Glyphs[0] != a.Glyphs[0] {
+		t.Errorf("JSON glyph should be %+v, not %+v", a.Glyphs[0], doc.Glyphs[0])
+	}
+}
+
+func TestAtlasWriteBMFont(t *testing.T) {
+	a := newTestAtlas(t)
+
+	var buf bytes.Buffer
+	if err := a.WriteBMFont(&buf, "Test", "test.png"); err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	out := buf.String()
+
+	g := findGlyph(a, 'A')
+	for _, line := range []string{
+		`info face="Test" size=20 `,
+		"common lineHeight=22 base=16 scaleW=32 ",
+		`page id=0 file="test.png"`,
+		"chars count=4",
+		"char id=65 x=" + strconv.Itoa(g.X) + " y=" + strconv.Itoa(g.Y) + " width=14 height=14 xoffset=-2 yoffset=4 xadvance=11 page=0 chnl=15",
+		"kernings count=1",
+		"kerning first=65 second=79 amount=-1",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("BMFont output should contain %q:\n%v", line, out)
+		}
+	}
+}
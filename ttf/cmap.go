@@ -0,0 +1,143 @@
+package ttf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// cmap maps characters to glyphs using one of the font's Unicode encoding subtables
+type cmap struct {
+	format uint16
+	data   []byte
+}
+
+func parseCmap(table []byte) (cmap, error) {
+	if len(table) < 4 {
+		return cmap{}, errors.New("ttf: cmap table is truncated")
+	}
+
+	// pick the subtable with the widest Unicode coverage
+	best, bestRank := -1, 0
+	for i, n := 0, int(u16(table, 2)); i < n; i++ {
+		if 4+8*i+8 > len(table) {
+			return cmap{}, errors.New("ttf: cmap table is truncated")
+		}
+		record := table[4+8*i:]
+		platform, encoding, offset := u16(record, 0), u16(record, 2), int(u32(record, 4))
+
+		rank := 0
+		switch {
+		case platform == 3 && encoding == 10, platform == 0 && encoding >= 4:
+			rank = 3 // full Unicode
+		case platform == 3 && encoding == 1, platform == 0:
+			rank = 2 // Basic Multilingual Plane
+		case platform == 3 && encoding == 0:
+			rank = 1 // Symbol
+		}
+		if rank > bestRank && offset+2 <= len(table) {
+			best, bestRank = offset, rank
+		}
+	}
+	if best < 0 {
+		return cmap{}, errors.New("ttf: font has no Unicode cmap subtable")
+	}
+
+	sub := table[best:]
+	c := cmap{format: u16(sub, 0), data: sub}
+	switch c.format {
+	case 4:
+		if len(sub) < 14 || len(sub) < 16+4*int(u16(sub, 6)) {
+			return cmap{}, errors.New("ttf: cmap format 4 subtable is truncated")
+		}
+	case 12:
+		if len(sub) < 16 || len(sub) < 16+12*int(u32(sub, 12)) {
+			return cmap{}, errors.New("ttf: cmap format 12 subtable is truncated")
+		}
+	default:
+		return cmap{}, fmt.Errorf("ttf: cmap format %v is not supported", c.format)
+	}
+	return c, nil
+}
+
+func (c cmap) lookup(r rune) GlyphIndex {
+	switch c.format {
+	case 4:
+		return c.lookup4(r)
+	case 12:
+		return c.lookup12(r)
+	}
+	return 0
+}
+
+func (c cmap) lookup4(r rune) GlyphIndex {
+	if r < 0 || r > 0xffff {
+		return 0
+	}
+	code := uint16(r)
+	d := c.data
+	segCount := int(u16(d, 6)) / 2
+
+	endCodes := 14
+	startCodes := endCodes + 2*segCount + 2
+	idDeltas := startCodes + 2*segCount
+	idRangeOffsets := idDeltas + 2*segCount
+
+	// binary search for the first segment ending at or after the code
+	lo, hi := 0, segCount
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if u16(d, endCodes+2*mid) < code {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == segCount {
+		return 0
+	}
+
+	seg := lo
+	start := u16(d, startCodes+2*seg)
+	if code < start {
+		return 0
+	}
+	delta := u16(d, idDeltas+2*seg)
+	rangeOffset := int(u16(d, idRangeOffsets+2*seg))
+	if rangeOffset == 0 {
+		return GlyphIndex(code + delta)
+	}
+
+	// the range offset is relative to its own position within the subtable
+	i := idRangeOffsets + 2*seg + rangeOffset + 2*int(code-start)
+	if i+2 > len(d) {
+		return 0
+	}
+	g := u16(d, i)
+	if g == 0 {
+		return 0
+	}
+	return GlyphIndex(g + delta)
+}
+
+func (c cmap) lookup12(r rune) GlyphIndex {
+	if r < 0 {
+		return 0
+	}
+	code := uint32(r)
+	d := c.data
+
+	lo, hi := 0, int(u32(d, 12))
+	for lo < hi {
+		mid := (lo + hi) / 2
+		group := d[16+12*mid:]
+		switch {
+		case code < u32(group, 0):
+			hi = mid
+		case code > u32(group, 4):
+			lo = mid + 1
+		default:
+			return GlyphIndex(u32(group, 8) + code - u32(group, 0))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package ttf
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// WriteJSON writes the atlas dimensions, glyph metrics and kerning as JSON. All metrics are in
+// pixels at the atlas font size.
+func (a *Atlas) WriteJSON(w io.Writer) error {
+	doc := struct {
+		Size       float64        `json:"size"`
+		Spread     float64        `json:"spread"`
+		Padding    int            `json:"padding"`
+		Width      int            `json:"width"`
+		Height     int            `json:"height"`
+		LineHeight float64        `json:"lineHeight"`
+		Ascent     float64        `json:"ascent"`
+		Glyphs     []AtlasGlyph   `json:"glyphs"`
+		Kerning    []AtlasKerning `json:"kerning"`
+	}{
+		a.Size, a.Spread, a.Padding, a.Field.Width, a.Field.Height, a.LineHeight, a.Ascent, a.Glyphs, a.Kerning,
+	}
+	if doc.Kerning == nil {
+		doc.Kerning = []AtlasKerning{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteBMFont writes the atlas in the AngelCode BMFont text format, with the atlas image stored
+// in the named page file. BMFont metrics are whole pixels so are rounded.
+func (a *Atlas) WriteBMFont(w io.Writer, face string, page string) error {
+	bw := bufio.NewWriter(w)
+	round := func(v float64) int { return int(math.Round(v)) }
+	base := round(a.Ascent)
+
+	fmt.Fprintf(bw, "info face=%q size=%v bold=0 italic=0 charset=\"\" unicode=1 stretchH=100 smooth=1 aa=1 padding=%v,%v,%v,%v spacing=0,0\n",
+		face, round(a.Size), a.Padding, a.Padding, a.Padding, a.Padding)
+	fmt.Fprintf(bw, "common lineHeight=%v base=%v scaleW=%v scaleH=%v pages=1 packed=0\n",
+		round(a.LineHeight), base, a.Field.Width, a.Field.Height)
+	fmt.Fprintf(bw, "page id=0 file=%q\n", page)
+
+	fmt.Fprintf(bw, "chars count=%v\n", len(a.Glyphs))
+	for _, g := range a.Glyphs {
+		fmt.Fprintf(bw, "char id=%v x=%v y=%v width=%v height=%v xoffset=%v yoffset=%v xadvance=%v page=0 chnl=15\n",
+			g.Rune, g.X, g.Y, g.Width, g.Height, round(g.BearingX), base-round(g.BearingY), round(g.Advance))
+	}
+
+	if len(a.Kerning) > 0 {
+		fmt.Fprintf(bw, "kernings count=%v\n", len(a.Kerning))
+		for _, k := range a.Kerning {
+			fmt.Fprintf(bw, "kerning first=%v second=%v amount=%v\n", k.First, k.Second, round(k.Amount))
+		}
+	}
+
+	return bw.Flush()
+}
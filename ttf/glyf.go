@@ -0,0 +1,304 @@
+package ttf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+// composite glyph component flags
+const (
+	argsAreWords  = 0x0001
+	argsAreXY     = 0x0002
+	haveScale     = 0x0008
+	moreGlyphs    = 0x0020
+	haveXYScale   = 0x0040
+	haveTwoByTwo  = 0x0080
+	maxGlyphDepth = 8
+)
+
+// simple glyph point flags
+const (
+	onCurve    = 0x01
+	xShort     = 0x02
+	yShort     = 0x04
+	repeatFlag = 0x08
+	xSame      = 0x10
+	ySame      = 0x20
+)
+
+// Glyph returns the outline of a glyph in font units. As in the font, y increases upwards from
+// the baseline and x from the pen position. Glyphs without an outline, such as space, return a
+// shape with no contours.
+func (f *Font) Glyph(g GlyphIndex) (*vector.Shape, error) {
+	shape := &vector.Shape{FillRule: vector.NonZero}
+	if err := f.appendGlyph(shape, g, func(v vector.Vec) vector.Vec { return v }, 0); err != nil {
+		return nil, err
+	}
+	return shape, nil
+}
+
+func (f *Font) glyphData(g GlyphIndex) ([]byte, error) {
+	i := int(g)
+	if i >= f.numGlyphs {
+		return nil, fmt.Errorf("ttf: glyph %v is out of range", g)
+	}
+
+	var start, end int
+	if f.longLoca {
+		start, end = int(u32(f.loca, 4*i)), int(u32(f.loca, 4*i+4))
+	} else {
+		start, end = 2*int(u16(f.loca, 2*i)), 2*int(u16(f.loca, 2*i+2))
+	}
+	if start > end || end > len(f.glyf) {
+		return nil, fmt.Errorf("ttf: glyph %v lies outside the glyf table", g)
+	}
+	return f.glyf[start:end], nil
+}
+
+func (f *Font) appendGlyph(shape *vector.Shape, g GlyphIndex, xf func(vector.Vec) vector.Vec, depth int) error {
+	if depth > maxGlyphDepth {
+		return errors.New("ttf: composite glyphs are nested too deeply")
+	}
+
+	data, err := f.glyphData(g)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if len(data) < 10 {
+		return fmt.Errorf("ttf: glyph %v is truncated", g)
+	}
+
+	numContours := int(int16(u16(data, 0)))
+	if numContours >= 0 {
+		return appendSimpleGlyph(shape, data, numContours, xf)
+	}
+	return f.appendCompositeGlyph(shape, data, xf, depth)
+}
+
+func appendSimpleGlyph(shape *vector.Shape, data []byte, numContours int, xf func(vector.Vec) vector.Vec) error {
+	truncated := errors.New("ttf: simple glyph is truncated")
+
+	p := 10
+	if len(data) < p+2*numContours+2 {
+		return truncated
+	}
+	ends := make([]int, numContours)
+	for i := range ends {
+		ends[i] = int(u16(data, p))
+		p += 2
+	}
+	numPoints := 0
+	if numContours > 0 {
+		numPoints = ends[numContours-1] + 1
+	}
+	p += 2 + int(u16(data, p)) // skip the hinting instructions
+
+	flags := make([]byte, 0, numPoints)
+	for len(flags) < numPoints {
+		if p >= len(data) {
+			return truncated
+		}
+		flag := data[p]
+		p++
+		flags = append(flags, flag)
+		if flag&repeatFlag != 0 {
+			if p >= len(data) {
+				return truncated
+			}
+			for n := data[p]; n > 0 && len(flags) < numPoints; n-- {
+				flags = append(flags, flag)
+			}
+			p++
+		}
+	}
+
+	// coordinates are stored as deltas, first all of the x values and then all of the y values
+	coords := func(short, same byte) ([]float64, error) {
+		values := make([]float64, numPoints)
+		v := 0
+		for i, flag := range flags {
+			switch {
+			case flag&short != 0:
+				if p >= len(data) {
+					return nil, truncated
+				}
+				d := int(data[p])
+				p++
+				if flag&same == 0 {
+					d = -d
+				}
+				v += d
+			case flag&same == 0:
+				if p+2 > len(data) {
+					return nil, truncated
+				}
+				v += int(int16(u16(data, p)))
+				p += 2
+			}
+			values[i] = float64(v)
+		}
+		return values, nil
+	}
+	xs, err := coords(xShort, xSame)
+	if err != nil {
+		return err
+	}
+	ys, err := coords(yShort, ySame)
+	if err != nil {
+		return err
+	}
+
+	start := 0
+	for _, end := range ends {
+		if end < start || end >= numPoints {
+			return errors.New("ttf: simple glyph has invalid contour end points")
+		}
+		pts := make([]vector.Vec, 0, end-start+1)
+		on := make([]bool, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			pts = append(pts, xf(vector.Vec{X: xs[i], Y: ys[i]}))
+			on = append(on, flags[i]&onCurve != 0)
+		}
+		if c := contour(pts, on); len(c) > 0 {
+			shape.Contours = append(shape.Contours, c)
+		}
+		start = end + 1
+	}
+	return nil
+}
+
+// contour converts a closed loop of TrueType points into segments. Consecutive off-curve points
+// have an implied on-curve point midway between them.
+func contour(pts []vector.Vec, on []bool) vector.Contour {
+	n := len(pts)
+	if n < 2 {
+		return nil
+	}
+
+	// rotate to begin on an on-curve point, using the implied one before the first point if there
+	// are none
+	first := -1
+	for i := range on {
+		if on[i] {
+			first = i
+			break
+		}
+	}
+	var start vector.Vec
+	if first < 0 {
+		start = pts[n-1].Lerp(pts[0], 0.5)
+		first = 0
+	} else {
+		start = pts[first]
+		first++
+	}
+
+	var c vector.Contour
+	add := func(s vector.Segment) {
+		if s.Point(0) != s.Point(1) {
+			c = append(c, s)
+		}
+	}
+
+	cur := start
+	var ctrl *vector.Vec
+	for k := 0; k < n; k++ {
+		i := (first + k) % n
+		p := pts[i]
+		switch {
+		case on[i] && ctrl == nil:
+			add(vector.Line{P0: cur, P1: p})
+			cur = p
+		case on[i]:
+			add(vector.Quadratic{P0: cur, P1: *ctrl, P2: p})
+			cur, ctrl = p, nil
+		case ctrl == nil:
+			ctrl = &pts[i]
+		default:
+			mid := ctrl.Lerp(p, 0.5)
+			add(vector.Quadratic{P0: cur, P1: *ctrl, P2: mid})
+			cur, ctrl = mid, &pts[i]
+		}
+	}
+
+	// close the loop back to the start
+	if ctrl != nil {
+		add(vector.Quadratic{P0: cur, P1: *ctrl, P2: start})
+	} else {
+		add(vector.Line{P0: cur, P1: start})
+	}
+	return c
+}
+
+func (f *Font) appendCompositeGlyph(shape *vector.Shape, data []byte, xf func(vector.Vec) vector.Vec, depth int) error {
+	truncated := errors.New("ttf: composite glyph is truncated")
+
+	p := 10
+	for {
+		if p+4 > len(data) {
+			return truncated
+		}
+		flags := u16(data, p)
+		component := GlyphIndex(u16(data, p+2))
+		p += 4
+
+		var dx, dy float64
+		if flags&argsAreWords != 0 {
+			if p+4 > len(data) {
+				return truncated
+			}
+			dx, dy = float64(int16(u16(data, p))), float64(int16(u16(data, p+2)))
+			p += 4
+		} else {
+			if p+2 > len(data) {
+				return truncated
+			}
+			dx, dy = float64(int8(data[p])), float64(int8(data[p+1]))
+			p += 2
+		}
+		if flags&argsAreXY == 0 {
+			return errors.New("ttf: composite glyphs positioned by point matching are not supported")
+		}
+
+		// the 2x2 transform is stored as 2.14 fixed point
+		a, b, c, d := 1.0, 0.0, 0.0, 1.0
+		f2dot14 := func(i int) float64 { return float64(int16(u16(data, i))) / (1 << 14) }
+		switch {
+		case flags&haveScale != 0:
+			if p+2 > len(data) {
+				return truncated
+			}
+			a = f2dot14(p)
+			d = a
+			p += 2
+		case flags&haveXYScale != 0:
+			if p+4 > len(data) {
+				return truncated
+			}
+			a, d = f2dot14(p), f2dot14(p+2)
+			p += 4
+		case flags&haveTwoByTwo != 0:
+			if p+8 > len(data) {
+				return truncated
+			}
+			a, b, c, d = f2dot14(p), f2dot14(p+2), f2dot14(p+4), f2dot14(p+6)
+			p += 8
+		}
+
+		componentXf := func(v vector.Vec) vector.Vec {
+			return xf(vector.Vec{X: a*v.X + c*v.Y + dx, Y: b*v.X + d*v.Y + dy})
+		}
+		if err := f.appendGlyph(shape, component, componentXf, depth+1); err != nil {
+			return err
+		}
+
+		if flags&moreGlyphs == 0 {
+			return nil
+		}
+	}
+}
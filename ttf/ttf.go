@@ -0,0 +1,208 @@
+// Package ttf reads glyph outlines and metrics from TrueType fonts and bakes them into
+// Signed-Distance-Field atlases for text rendering.
+//
+// Only the tables needed for that are read: glyf/loca outlines, cmap character mapping,
+// hhea/hmtx horizontal metrics and format 0 kern pairs. Kerning held in GPOS is not supported.
+package ttf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// GlyphIndex identifies a glyph within a font. Index 0 is the font's missing-glyph symbol.
+type GlyphIndex uint16
+
+// HMetric is the horizontal layout of a glyph in font units
+type HMetric struct {
+	// AdvanceWidth is how far the pen moves after drawing the glyph
+	AdvanceWidth int
+	// LeftSideBearing is the distance from the pen position to the left of the glyph's bounds
+	LeftSideBearing int
+}
+
+// Font is a parsed TrueType font
+type Font struct {
+	// UnitsPerEm is the size of the em square that outlines and metrics are measured in
+	UnitsPerEm int
+	// Ascent is the distance from the baseline to the top of the tallest glyphs, in font units
+	Ascent int
+	// Descent is the (negative) distance from the baseline to the bottom of the lowest glyphs
+	Descent int
+	// LineGap is the extra spacing to add between lines, in font units
+	LineGap int
+
+	numGlyphs   int
+	longLoca    bool
+	numHMetrics int
+
+	cmap cmap
+	glyf []byte
+	loca []byte
+	hmtx []byte
+	kern map[uint32]int
+}
+
+// Parse reads a TrueType font from its file contents
+func Parse(data []byte) (*Font, error) {
+	if len(data) < 12 {
+		return nil, errors.New("ttf: font data is too short")
+	}
+
+	switch version := u32(data, 0); version {
+	case 0x00010000, 0x74727565: // 1.0 or 'true'
+	case 0x4f54544f: // 'OTTO'
+		return nil, errors.New("ttf: CFF outlines are not supported")
+	case 0x74746366: // 'ttcf'
+		return nil, errors.New("ttf: font collections are not supported")
+	default:
+		return nil, fmt.Errorf("ttf: unknown font version %#08x", version)
+	}
+
+	tables := make(map[string][]byte)
+	numTables := int(u16(data, 4))
+	if len(data) < 12+16*numTables {
+		return nil, errors.New("ttf: table directory is truncated")
+	}
+	for i := 0; i < numTables; i++ {
+		record := data[12+16*i:]
+		offset, length := int(u32(record, 8)), int(u32(record, 12))
+		if offset < 0 || length < 0 || offset+length > len(data) || offset+length < offset {
+			return nil, fmt.Errorf("ttf: table %q lies outside the font data", record[:4])
+		}
+		tables[string(record[:4])] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "maxp", "hhea", "hmtx", "cmap", "loca", "glyf"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("ttf: font has no %q table", tag)
+		}
+	}
+
+	head, maxp, hhea := tables["head"], tables["maxp"], tables["hhea"]
+	if len(head) < 54 || len(maxp) < 6 || len(hhea) < 36 {
+		return nil, errors.New("ttf: font header tables are truncated")
+	}
+
+	f := &Font{
+		UnitsPerEm:  int(u16(head, 18)),
+		Ascent:      int(int16(u16(hhea, 4))),
+		Descent:     int(int16(u16(hhea, 6))),
+		LineGap:     int(int16(u16(hhea, 8))),
+		numGlyphs:   int(u16(maxp, 4)),
+		longLoca:    u16(head, 50) != 0,
+		numHMetrics: int(u16(hhea, 34)),
+		glyf:        tables["glyf"],
+		loca:        tables["loca"],
+		hmtx:        tables["hmtx"],
+	}
+
+	if f.UnitsPerEm == 0 {
+		return nil, errors.New("ttf: font has zero units per em")
+	}
+	if f.numHMetrics == 0 || len(f.hmtx) < 4*f.numHMetrics {
+		return nil, errors.New("ttf: hmtx table is truncated")
+	}
+	locaSize := 2
+	if f.longLoca {
+		locaSize = 4
+	}
+	if len(f.loca) < locaSize*(f.numGlyphs+1) {
+		return nil, errors.New("ttf: loca table is truncated")
+	}
+
+	var err error
+	if f.cmap, err = parseCmap(tables["cmap"]); err != nil {
+		return nil, err
+	}
+	if f.kern, err = parseKern(tables["kern"]); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// NumGlyphs returns the number of glyphs in the font
+func (f *Font) NumGlyphs() int {
+	return f.numGlyphs
+}
+
+// Index returns the glyph for a character, or 0 (the missing glyph) if the font has none
+func (f *Font) Index(r rune) GlyphIndex {
+	return f.cmap.lookup(r)
+}
+
+// HMetric returns the horizontal metrics of a glyph
+func (f *Font) HMetric(g GlyphIndex) HMetric {
+	i := int(g)
+	if i >= f.numGlyphs {
+		return HMetric{}
+	}
+
+	// glyphs past the last full metric share its advance and only store their bearing
+	if i < f.numHMetrics {
+		return HMetric{
+			AdvanceWidth:    int(u16(f.hmtx, 4*i)),
+			LeftSideBearing: int(int16(u16(f.hmtx, 4*i+2))),
+		}
+	}
+
+	m := HMetric{AdvanceWidth: int(u16(f.hmtx, 4*(f.numHMetrics-1)))}
+	if off := 4*f.numHMetrics + 2*(i-f.numHMetrics); off+2 <= len(f.hmtx) {
+		m.LeftSideBearing = int(int16(u16(f.hmtx, off)))
+	}
+	return m
+}
+
+// Kern returns the adjustment to the advance between a pair of glyphs, in font units
+func (f *Font) Kern(left, right GlyphIndex) int {
+	return f.kern[uint32(left)<<16|uint32(right)]
+}
+
+func parseKern(kern []byte) (map[uint32]int, error) {
+	pairs := make(map[uint32]int)
+	if len(kern) < 4 || u16(kern, 0) != 0 {
+		// a missing table means no kerning, and the Apple variant is not supported
+		return pairs, nil
+	}
+
+	offset := 4
+	for i, n := 0, int(u16(kern, 2)); i < n; i++ {
+		if offset+6 > len(kern) {
+			return nil, errors.New("ttf: kern table is truncated")
+		}
+		length, coverage := int(u16(kern, offset+2)), u16(kern, offset+4)
+
+		// only horizontal, non-minimum, non-cross-stream format 0 subtables hold plain pairs
+		if coverage&0xff07 == 0x0001 {
+			sub := kern[offset+6:]
+			if len(sub) < 8 {
+				return nil, errors.New("ttf: kern table is truncated")
+			}
+			nPairs := int(u16(sub, 0))
+			if len(sub) < 8+6*nPairs {
+				return nil, errors.New("ttf: kern table is truncated")
+			}
+			for j := 0; j < nPairs; j++ {
+				pair := sub[8+6*j:]
+				pairs[u32(pair, 0)] += int(int16(u16(pair, 4)))
+			}
+		}
+
+		if length < 6 {
+			return nil, errors.New("ttf: kern subtable has an invalid length")
+		}
+		offset += length
+	}
+
+	return pairs, nil
+}
+
+func u16(b []byte, i int) uint16 {
+	return binary.BigEndian.Uint16(b[i:])
+}
+
+func u32(b []byte, i int) uint32 {
+	return binary.BigEndian.Uint32(b[i:])
+}
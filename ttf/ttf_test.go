@@ -0,0 +1,369 @@
+package ttf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/daveagill/go-sdf/vector"
+)
+
+type pt struct {
+	x, y int
+	on   bool
+}
+
+// encodeSimpleGlyph encodes contours the way font tools do, with short and repeated-flag forms
+func encodeSimpleGlyph(contours ...[]pt) []byte {
+	var b bytes.Buffer
+	w := func(v interface{}) { binary.Write(&b, binary.BigEndian, v) }
+
+	w(int16(len(contours)))
+	w([4]int16{}) // bounds are recomputed from the outline so are left empty
+
+	var pts []pt
+	for _, c := range contours {
+		pts = append(pts, c...)
+		w(uint16(len(pts) - 1))
+	}
+	w(uint16(1)) // one byte of instructions
+	b.WriteByte(0)
+
+	var flags, xs, ys []byte
+	encode := func(delta int, short, same byte, coords []byte) (byte, []byte) {
+		switch {
+		case delta == 0:
+			return same, coords
+		case delta >= -255 && delta <= 255:
+			if delta > 0 {
+				return short | same, append(coords, byte(delta))
+			}
+			return short, append(coords, byte(-delta))
+		}
+		return 0, append(coords, byte(uint16(delta)>>8), byte(delta))
+	}
+	px, py := 0, 0
+	for _, p := range pts {
+		var fx, fy byte
+		fx, xs = encode(p.x-px, xShort, xSame, xs)
+		fy, ys = encode(p.y-py, yShort, ySame, ys)
+		flag := fx | fy
+		if p.on {
+			flag |= onCurve
+		}
+		px, py = p.x, p.y
+
+		// collapse a run of identical flags
+		n := len(flags)
+		switch {
+		case n >= 2 && flags[n-2] == flag|repeatFlag && flags[n-1] < 255:
+			flags[n-1]++
+		case n >= 1 && flags[n-1] == flag:
+			flags[n-1] = flag | repeatFlag
+			flags = append(flags, 1)
+		default:
+			flags = append(flags, flag)
+		}
+	}
+
+	b.Write(flags)
+	b.Write(xs)
+	b.Write(ys)
+	return b.Bytes()
+}
+
+func encodeCompositeGlyph(component uint16, dx, dy int16, scale float64) []byte {
+	var b bytes.Buffer
+	w := func(v interface{}) { binary.Write(&b, binary.BigEndian, v) }
+
+	w(int16(-1))
+	w([4]int16{})
+	w(uint16(argsAreWords | argsAreXY | haveScale))
+	w(component)
+	w(dx)
+	w(dy)
+	w(int16(scale * (1 << 14)))
+	return b.Bytes()
+}
+
+// buildFont assembles a minimal TrueType font with the glyphs:
+//
+//	0: missing glyph, empty
+//	1: 'A', a 1000 unit square with a 500 unit square hole
+//	2: 'O', a 4-point contour of only off-curve points, approximating a circle
+//	3: 'B', glyph 1 scaled by 0.5 and moved right by 100 units
+//	4: ' ', empty
+func buildFont() []byte {
+	glyphs := [][]byte{
+		nil,
+		encodeSimpleGlyph(
+			[]pt{{0, 0, true}, {0, 1000, true}, {1000, 1000, true}, {1000, 0, true}},
+			[]pt{{250, 250, true}, {750, 250, true}, {750, 750, true}, {250, 750, true}},
+		),
+		encodeSimpleGlyph(
+			[]pt{{0, 0, false}, {0, 1000, false}, {1000, 1000, false}, {1000, 0, false}},
+		),
+		encodeCompositeGlyph(1, 100, 0, 0.5),
+		nil,
+	}
+
+	var glyf bytes.Buffer
+	var loca []uint32
+	for _, g := range glyphs {
+		loca = append(loca, uint32(glyf.Len()))
+		glyf.Write(g)
+		for glyf.Len()%4 != 0 {
+			glyf.WriteByte(0)
+		}
+	}
+	loca = append(loca, uint32(glyf.Len()))
+
+	be := func(vs ...interface{}) []byte {
+		var b bytes.Buffer
+		for _, v := range vs {
+			binary.Write(&b, binary.BigEndian, v)
+		}
+		return b.Bytes()
+	}
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 2000) // unitsPerEm
+	binary.BigEndian.PutUint16(head[50:], 1)    // long loca
+
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[4:], 1600)                // ascent
+	binary.BigEndian.PutUint16(hhea[6:], uint16(-400&0xffff)) // descent
+	binary.BigEndian.PutUint16(hhea[8:], 200)                 // line gap
+	binary.BigEndian.PutUint16(hhea[34:], 3)                  // number of full hmetrics
+
+	// ' ', 'A' and 'B' map through a delta that wraps around 16 bits, 'O' through the glyph id array
+	delta := func(code rune, g int) uint16 { return uint16(g - int(code)) }
+	segments := []struct{ start, end, delta, rangeOffset uint16 }{
+		{' ', ' ', delta(' ', 4), 0},
+		{'A', 'A', delta('A', 1), 0},
+		{'B', 'B', delta('B', 3), 0},
+		{'O', 'O', 0, 0},
+		{0xffff, 0xffff, 1, 0},
+	}
+	segCount := len(segments)
+	cmap4 := be(uint16(4), uint16(0), uint16(0), uint16(2*segCount), uint16(0), uint16(0), uint16(0))
+	for _, s := range segments {
+		cmap4 = append(cmap4, be(s.end)...)
+	}
+	cmap4 = append(cmap4, 0, 0)
+	for _, s := range segments {
+		cmap4 = append(cmap4, be(s.start)...)
+	}
+	for _, s := range segments {
+		cmap4 = append(cmap4, be(s.delta)...)
+	}
+	for i, s := range segments {
+		if s.start == 'O' {
+			// point to the glyph id array just past the range offsets
+			s.rangeOffset = uint16(2 * (segCount - i))
+		}
+		cmap4 = append(cmap4, be(s.rangeOffset)...)
+	}
+	cmap4 = append(cmap4, be(uint16(2))...)
+	binary.BigEndian.PutUint16(cmap4[2:], uint16(len(cmap4)))
+
+	tables := map[string][]byte{
+		"head": head,
+		"hhea": hhea,
+		"maxp": be(uint32(0x00005000), uint16(len(glyphs))),
+		"hmtx": be(
+			uint16(0), int16(0),
+			uint16(1100), int16(0),
+			uint16(1200), int16(0),
+			int16(100), int16(0),
+		),
+		"cmap": append(be(uint16(0), uint16(1), uint16(3), uint16(1), uint32(12)), cmap4...),
+		"loca": be(loca),
+		"glyf": glyf.Bytes(),
+		"kern": be(
+			uint16(0), uint16(1), // version, table count
+			uint16(0), uint16(6+8+6), uint16(0x0001), // subtable header
+			uint16(1), uint16(0), uint16(0), uint16(0), // pair count and search fields
+			uint16(1), uint16(2), int16(-100), // 'A' 'O'
+		),
+	}
+
+	var tags []string
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	var font bytes.Buffer
+	font.Write(be(uint32(0x00010000), uint16(len(tables)), uint16(0), uint16(0), uint16(0)))
+	offset := 12 + 16*len(tables)
+	var data bytes.Buffer
+	for _, tag := range tags {
+		t := tables[tag]
+		font.WriteString(tag)
+		font.Write(be(uint32(0), uint32(offset+data.Len()), uint32(len(t))))
+		data.Write(t)
+		for data.Len()%4 != 0 {
+			data.WriteByte(0)
+		}
+	}
+	font.Write(data.Bytes())
+	return font.Bytes()
+}
+
+func parseTestFont(t *testing.T) *Font {
+	f, err := Parse(buildFont())
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	return f
+}
+
+func TestParse(t *testing.T) {
+	f := parseTestFont(t)
+
+	if f.UnitsPerEm != 2000 || f.Ascent != 1600 || f.Descent != -400 || f.LineGap != 200 {
+		t.Errorf("Font metrics are wrong: %v %v %v %v", f.UnitsPerEm, f.Ascent, f.Descent, f.LineGap)
+	}
+	if f.NumGlyphs() != 5 {
+		t.Errorf("Font should have 5 glyphs, not %v", f.NumGlyphs())
+	}
+}
+
+func TestIndex(t *testing.T) {
+	f := parseTestFont(t)
+
+	for r, expected := range map[rune]GlyphIndex{' ': 4, 'A': 1, 'B': 3, 'O': 2, 'C': 0, '€': 0} {
+		if g := f.Index(r); g != expected {
+			t.Errorf("Glyph for %q should be %v, not %v", r, expected, g)
+		}
+	}
+}
+
+func TestHMetric(t *testing.T) {
+	f := parseTestFont(t)
+
+	if m := f.HMetric(1); m.AdvanceWidth != 1100 || m.LeftSideBearing != 0 {
+		t.Errorf("Glyph 1 metrics should be {1100 0}, not %v", m)
+	}
+	// glyphs past the last full metric repeat its advance
+	if m := f.HMetric(3); m.AdvanceWidth != 1200 || m.LeftSideBearing != 100 {
+		t.Errorf("Glyph 3 metrics should be {1200 100}, not %v", m)
+	}
+}
+
+func TestKern(t *testing.T) {
+	f := parseTestFont(t)
+
+	if k := f.Kern(1, 2); k != -100 {
+		t.Errorf("Kerning between glyphs 1 and 2 should be -100, not %v", k)
+	}
+	if k := f.Kern(2, 1); k != 0 {
+		t.Errorf("Kerning between glyphs 2 and 1 should be 0, not %v", k)
+	}
+}
+
+func TestGlyphSimple(t *testing.T) {
+	f := parseTestFont(t)
+
+	shape, err := f.Glyph(1)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	if len(shape.Contours) != 2 || len(shape.Contours[0]) != 4 || len(shape.Contours[1]) != 4 {
+		t.Fatalf("Glyph should have two contours of 4 lines, not %v", shape.Contours)
+	}
+
+	tests := []struct {
+		p        vector.Vec
+		distance float64
+	}{
+		{vector.Vec{X: 100, Y: 500}, -100},
+		{vector.Vec{X: 500, Y: 500}, 250},
+		{vector.Vec{X: 1100, Y: 500}, 100},
+	}
+	for _, tt := range tests {
+		if d := shape.SignedDistance(tt.p); math.Abs(d-tt.distance) > 1e-9 {
+			t.Errorf("Signed distance at %v should be %v, not %v", tt.p, tt.distance, d)
+		}
+	}
+}
+
+func TestGlyphOffCurve(t *testing.T) {
+	f := parseTestFont(t)
+
+	shape, err := f.Glyph(2)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	// implied on-curve points lie midway between the off-curve points
+	if len(shape.Contours) != 1 || len(shape.Contours[0]) != 4 {
+		t.Fatalf("Glyph should have one contour of 4 curves, not %v", shape.Contours)
+	}
+	for _, seg := range shape.Contours[0] {
+		q, ok := seg.(vector.Quadratic)
+		if !ok {
+			t.Fatalf("Segments should be quadratic, not %T", seg)
+		}
+		if q.P0.X != 500 && q.P0.Y != 500 {
+			t.Errorf("Curves should start at the mid-point of an edge, not %v", q.P0)
+		}
+	}
+	if !shape.Contains(vector.Vec{X: 500, Y: 500}) || shape.Contains(vector.Vec{X: 50, Y: 50}) {
+		t.Errorf("Glyph should contain its centre but not its corners")
+	}
+}
+
+func TestGlyphComposite(t *testing.T) {
+	f := parseTestFont(t)
+
+	shape, err := f.Glyph(3)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	min, max := shape.Bounds()
+	if min != (vector.Vec{X: 100, Y: 0}) || max != (vector.Vec{X: 600, Y: 500}) {
+		t.Errorf("Composite glyph should span (100, 0)-(600, 500), not %v-%v", min, max)
+	}
+}
+
+func TestGlyphEmpty(t *testing.T) {
+	f := parseTestFont(t)
+
+	shape, err := f.Glyph(4)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+	if len(shape.Contours) != 0 {
+		t.Errorf("Space glyph should have no contours")
+	}
+
+	if _, err := f.Glyph(5); err == nil {
+		t.Errorf("Out of range glyphs should return an error")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	font := buildFont()
+
+	if _, err := Parse(font[:8]); err == nil {
+		t.Errorf("Truncated font should return an error")
+	}
+
+	otf := append([]byte("OTTO"), font[4:]...)
+	if _, err := Parse(otf); err == nil {
+		t.Errorf("CFF font should return an error")
+	}
+
+	// corrupt the offset of the first table
+	bad := append([]byte{}, font...)
+	binary.BigEndian.PutUint32(bad[12+8:], uint32(len(font)))
+	if _, err := Parse(bad); err == nil {
+		t.Errorf("Table outside the font data should return an error")
+	}
+}
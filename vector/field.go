@@ -0,0 +1,48 @@
+package vector
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// Field returns an SDF of the given size holding the exact signed distance from the centre of
+// each pixel to the outline of the shape. Unlike computing the field from a Stencil this has no
+// rasterisation error.
+func (s *Shape) Field(w, h int) *sdf.SDF {
+	return Bake(s.SignedDistance, w, h)
+}
+
+// Bake returns an SDF of the given size holding the signed distance given by f at the centre of
+// each pixel. f is called concurrently so must be safe for concurrent use.
+func Bake(f func(p Vec) float64, w, h int) *sdf.SDF {
+	field := sdf.New(w, h)
+
+	// each row is independent so they are shared out between the CPUs
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for y := range rows {
+				for x := 0; x < w; x++ {
+					field.Set(x, y, f(pixelCentre(x, y)))
+				}
+			}
+		}()
+	}
+
+	for y := 0; y < h; y++ {
+		rows <- y
+	}
+	close(rows)
+	wg.Wait()
+
+	return field
+}
+
+func pixelCentre(x, y int) Vec {
+	return Vec{float64(x) + 0.5, float64(y) + 0.5}
+}
@@ -0,0 +1,24 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShapeField(t *testing.T) {
+	s := &Shape{Contours: []Contour{circle(8, 7, 5)}}
+	field := s.Field(16, 14)
+
+	if field.Width != 16 || field.Height != 14 {
+		t.Fatalf("Field size should be (16, 14), not (%v, %v)", field.Width, field.Height)
+	}
+
+	for y := 0; y < 14; y++ {
+		for x := 0; x < 16; x++ {
+			expected := math.Hypot(float64(x)+0.5-8, float64(y)+0.5-7) - 5
+			if f := field.At(x, y); math.Abs(f-expected) > 0.01 {
+				t.Errorf("Field value at (%v, %v) should be about %v, not %v", x, y, expected, f)
+			}
+		}
+	}
+}
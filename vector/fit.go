@@ -0,0 +1,179 @@
+package vector
+
+import "math"
+
+// cornerTurn is the sharpest turn in radians that FitCubics smooths over rather than keeping as
+// a corner
+const cornerTurn = math.Pi / 3
+
+// FitCubics returns a contour of cubic Bézier curves that passes within tolerance of every point
+// of the polyline, using Schneider's curve fitting algorithm. Points where the polyline turns by
+// more than 60 degrees are kept as corners, as are both ends of an edge shorter than twice the
+// tolerance that together turn that much, such as the corners cut off by Trace. A run between two
+// corners with no points between them is kept as a straight line.
+func (p Polyline) FitCubics(tolerance float64) Contour {
+	n := len(p)
+	if n < 3 {
+		return p.Contour()
+	}
+
+	turn := func(i int) float64 {
+		in := p[(i+n)%n].Sub(p[(i+n-1)%n])
+		out := p[(i+1)%n].Sub(p[(i+n)%n])
+		return math.Atan2(in.Cross(out), in.Dot(out))
+	}
+	short := func(i int) bool {
+		return p[(i+1)%n].Sub(p[(i+n)%n]).Len() < 2*tolerance
+	}
+
+	var corners []int
+	for i := range p {
+		if math.Abs(turn(i)) > cornerTurn ||
+			short(i) && math.Abs(turn(i)+turn(i+1)) > cornerTurn ||
+			short(i-1) && math.Abs(turn(i-1)+turn(i)) > cornerTurn {
+			corners = append(corners, i)
+		}
+	}
+
+	// a smooth loop is fitted from its first point all the way around, matching the tangents
+	// where it meets itself
+	if len(corners) == 0 {
+		loop := append(append([]Vec{}, p...), p[0])
+		tangent := p[1].Sub(p[n-1]).Normalize()
+		return fitCubic(loop, tangent, tangent.Scale(-1), tolerance*tolerance)
+	}
+
+	var c Contour
+	for k, from := range corners {
+		to := corners[(k+1)%len(corners)]
+		if to <= from {
+			to += n
+		}
+
+		run := make([]Vec, 0, to-from+1)
+		for i := from; i <= to; i++ {
+			run = append(run, p[i%n])
+		}
+		if len(run) == 2 {
+			c = append(c, Line{run[0], run[1]})
+			continue
+		}
+
+		start := run[1].Sub(run[0]).Normalize()
+		end := run[len(run)-2].Sub(run[len(run)-1]).Normalize()
+		c = append(c, fitCubic(run, start, end, tolerance*tolerance)...)
+	}
+	return c
+}
+
+// fitCubic fits cubics through the points, leaving the first point along the unit tangent start
+// and arriving at the last from along the unit tangent end, and splitting the points wherever a
+// single cubic strays further than the square root of maxError from them
+func fitCubic(points []Vec, start, end Vec, maxError float64) Contour {
+	first, last := points[0], points[len(points)-1]
+	if len(points) == 2 {
+		d := last.Sub(first).Len() / 3
+		return Contour{Cubic{first, first.Add(start.Scale(d)), last.Add(end.Scale(d)), last}}
+	}
+
+	u := chordLengths(points)
+	curve := bezierThrough(points, u, start, end)
+	err, split := fitError(points, u, curve)
+	if err < maxError {
+		return Contour{curve}
+	}
+
+	// a near miss is often fixed by better matching the points to parameters along the curve
+	if err < 4*maxError {
+		for i := 0; i < 4; i++ {
+			u = reparameterize(points, u, curve)
+			curve = bezierThrough(points, u, start, end)
+			if err, split = fitError(points, u, curve); err < maxError {
+				return Contour{curve}
+			}
+		}
+	}
+
+	centre := points[split-1].Sub(points[split+1]).Normalize()
+	left := fitCubic(points[:split+1], start, centre, maxError)
+	right := fitCubic(points[split:], centre.Scale(-1), end, maxError)
+	return append(left, right...)
+}
+
+// chordLengths returns parameters for the points in range [0, 1] in proportion to the distance
+// along the polyline through them
+func chordLengths(points []Vec) []float64 {
+	u := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		u[i] = u[i-1] + points[i].Sub(points[i-1]).Len()
+	}
+	for i := range u {
+		if total := u[len(u)-1]; total > 0 {
+			u[i] /= total
+		}
+	}
+	return u
+}
+
+// bezierThrough returns the cubic with the given end tangents that is the least squares fit of
+// the points at parameters u
+func bezierThrough(points []Vec, u []float64, start, end Vec) Cubic {
+	first, last := points[0], points[len(points)-1]
+
+	var c00, c01, c11, x0, x1 float64
+	for i, t := range u {
+		mt := 1 - t
+		b0, b1, b2, b3 := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+		a0, a1 := start.Scale(b1), end.Scale(b2)
+
+		c00 += a0.Dot(a0)
+		c01 += a0.Dot(a1)
+		c11 += a1.Dot(a1)
+
+		rest := points[i].Sub(first.Scale(b0 + b1)).Sub(last.Scale(b2 + b3))
+		x0 += a0.Dot(rest)
+		x1 += a1.Dot(rest)
+	}
+
+	alpha0, alpha1 := 0.0, 0.0
+	if det := c00*c11 - c01*c01; det != 0 {
+		alpha0 = (x0*c11 - c01*x1) / det
+		alpha1 = (c00*x1 - c01*x0) / det
+	}
+
+	// fall back on a third of the distance between the ends when the fit has no sensible length
+	length := last.Sub(first).Len()
+	if eps := 1e-6 * length; alpha0 < eps || alpha1 < eps {
+		alpha0, alpha1 = length/3, length/3
+	}
+
+	return Cubic{first, first.Add(start.Scale(alpha0)), last.Add(end.Scale(alpha1)), last}
+}
+
+// fitError returns the greatest squared distance of a point from the curve at its parameter, and
+// the index of that point
+func fitError(points []Vec, u []float64, c Cubic) (float64, int) {
+	max, split := 0.0, len(points)/2
+	for i := 1; i < len(points)-1; i++ {
+		d := c.Point(u[i]).Sub(points[i])
+		if e := d.Dot(d); e > max {
+			max, split = e, i
+		}
+	}
+	return max, split
+}
+
+// reparameterize improves the parameters of the points to be nearer to the points on the curve
+// closest to them, by a step of Newton's method
+func reparameterize(points []Vec, u []float64, c Cubic) []float64 {
+	ret := make([]float64, len(u))
+	for i, t := range u {
+		d := c.Point(t).Sub(points[i])
+		d1, d2 := c.Direction(t), c.secondDerivative(t)
+		ret[i] = t
+		if denom := d1.Dot(d1) + d.Dot(d2); denom != 0 {
+			ret[i] = clamp01(t - d.Dot(d1)/denom)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,78 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitCubicsCircle(t *testing.T) {
+	var p Polyline
+	for i := 0; i < 64; i++ {
+		a := 2 * math.Pi * float64(i) / 64
+		p = append(p, Vec{10 + 8*math.Cos(a), 10 + 8*math.Sin(a)})
+	}
+
+	c := p.FitCubics(0.1)
+	if len(c) > 8 {
+		t.Errorf("Circle should fit to a few cubics, not %v", len(c))
+	}
+	for i, seg := range c {
+		if _, ok := seg.(Cubic); !ok {
+			t.Errorf("Segment %v should be a cubic, not %T", i, seg)
+		}
+		if next := c[(i+1)%len(c)]; seg.Point(1) != next.Point(0) {
+			t.Errorf("Segment %v should end where the next starts", i)
+		}
+		for k := 0; k <= 10; k++ {
+			if r := seg.Point(float64(k) / 10).Sub(Vec{10, 10}).Len(); math.Abs(r-8) > 0.15 {
+				t.Errorf("Segment %v should stay near the circle, not be %v from its centre", i, r)
+			}
+		}
+	}
+}
+
+func TestFitCubicsCorners(t *testing.T) {
+	// a square keeps its corners and straight sides
+	p := Polyline{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	c := p.FitCubics(0.1)
+	if len(c) != 4 {
+		t.Fatalf("Square should fit to 4 segments, not %v", len(c))
+	}
+	for i, seg := range c {
+		if _, ok := seg.(Line); !ok {
+			t.Errorf("Side %v should be a line, not %T", i, seg)
+		}
+		if seg.Point(0) != p[i] {
+			t.Errorf("Side %v should start at the corner %v, not %v", i, p[i], seg.Point(0))
+		}
+	}
+
+	// corners cut off into two shallower turns are kept too
+	cut := Polyline{{0, 0.5}, {0.5, 0}, {10, 0}, {10, 10}, {0, 10}}
+	c = cut.FitCubics(0.5)
+	if len(c) != 5 {
+		t.Errorf("Cut square should fit to 5 lines, not %v", c)
+	}
+	for i, seg := range c {
+		if _, ok := seg.(Line); !ok {
+			t.Errorf("Side %v of the cut square should be a line, not %T", i, seg)
+		}
+	}
+
+	// a D shape keeps the corners where its curve meets its straight side
+	var d Polyline
+	for i := 0; i <= 16; i++ {
+		a := math.Pi/2 - math.Pi*float64(i)/16
+		d = append(d, Vec{8 * math.Cos(a), -8 * math.Sin(a)})
+	}
+	c = d.FitCubics(0.1)
+	for _, corner := range []Vec{{0, -8}, {0, 8}} {
+		found := false
+		for _, seg := range c {
+			found = found || seg.Point(0).Sub(corner).Len() < 1e-9
+		}
+		if !found {
+			t.Errorf("Corner %v should start a segment", corner)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseShape parses a textual shape description, a subset of the format used by msdfgen.
+//
+// Each contour is enclosed in curly braces and lists its points separated by semicolons. The
+// points of a contour are joined by straight lines, unless one (quadratic) or two (cubic) control
+// points, also separated by a semicolon, are given in parentheses between them. A '#' in place
+// of a point joins back to the first point. Contours are always closed. For example a square
+// with one curved side:
+//
+//	{ 0, 0; 10, 0; (15, 5); 10, 10; 0, 10; # }
+func ParseShape(desc string) (*Shape, error) {
+	p := &shapeParser{s: desc}
+	shape := &Shape{}
+
+	for p.skipSpace(); p.pos < len(p.s); p.skipSpace() {
+		c, err := p.contour()
+		if err != nil {
+			return nil, err
+		}
+		shape.Contours = append(shape.Contours, c)
+	}
+
+	return shape, nil
+}
+
+type shapeParser struct {
+	s   string
+	pos int
+}
+
+func (p *shapeParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("shape description at offset %v: %v", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *shapeParser) skipSpace() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// accept consumes the next non-space character if it is c
+func (p *shapeParser) accept(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *shapeParser) expect(c byte) error {
+	if !p.accept(c) {
+		return p.errorf("expected '%c'", c)
+	}
+	return nil
+}
+
+func (p *shapeParser) number() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && strings.ContainsRune("+-.0123456789eE", rune(p.s[p.pos])) {
+		p.pos++
+	}
+
+	v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("expected a number")
+	}
+	return v, nil
+}
+
+func (p *shapeParser) point() (Vec, error) {
+	x, err := p.number()
+	if err != nil {
+		return Vec{}, err
+	}
+	if err := p.expect(','); err != nil {
+		return Vec{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return Vec{}, err
+	}
+	return Vec{x, y}, nil
+}
+
+// controls parses the parenthesised control points between two points, if any
+func (p *shapeParser) controls() ([]Vec, error) {
+	if !p.accept('(') {
+		return nil, nil
+	}
+
+	ctrl := []Vec{}
+	for {
+		v, err := p.point()
+		if err != nil {
+			return nil, err
+		}
+		ctrl = append(ctrl, v)
+
+		if p.accept(')') {
+			break
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ctrl) > 2 {
+		return nil, p.errorf("at most 2 control points are allowed between points")
+	}
+	return ctrl, p.expect(';')
+}
+
+func (p *shapeParser) contour() (Contour, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	c := Contour{}
+	if p.accept('}') {
+		return nil, errors.New("shape description contains an empty contour")
+	}
+
+	first, err := p.point()
+	if err != nil {
+		return nil, err
+	}
+
+	last := first
+	closed := false
+	for !closed && p.accept(';') {
+		ctrl, err := p.controls()
+		if err != nil {
+			return nil, err
+		}
+
+		var next Vec
+		if p.accept('#') {
+			next, closed = first, true
+		} else if len(ctrl) == 0 && p.peekIs('}') {
+			// a trailing semicolon before the closing brace
+			break
+		} else if next, err = p.point(); err != nil {
+			return nil, err
+		}
+
+		c = append(c, makeSegment(last, ctrl, next))
+		last = next
+	}
+
+	if err := p.expect('}'); err != nil {
+		return nil, err
+	}
+
+	// close the contour back to its start if it was not explicitly closed
+	if last != first {
+		c = append(c, Line{last, first})
+	}
+	if len(c) == 0 {
+		return nil, errors.New("shape description contains a contour with only one point")
+	}
+
+	return c, nil
+}
+
+func (p *shapeParser) peekIs(c byte) bool {
+	p.skipSpace()
+	return p.pos < len(p.s) && p.s[p.pos] == c
+}
+
+func makeSegment(from Vec, ctrl []Vec, to Vec) Segment {
+	switch len(ctrl) {
+	case 1:
+		return Quadratic{from, ctrl[0], to}
+	case 2:
+		return Cubic{from, ctrl[0], ctrl[1], to}
+	}
+	return Line{from, to}
+}
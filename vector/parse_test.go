@@ -0,0 +1,49 @@
+package vector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseShape(t *testing.T) {
+	s, err := ParseShape(`
+		{ 0, 0; 10, 0; (15, 5); 10, 10; (5, 15; 2, 12); 0, 10; # }
+		{ 2,2; 3,2; 3,3 }
+	`)
+	if err != nil {
+		t.Fatalf("Error should have been nil, not %v", err)
+	}
+
+	expected := &Shape{Contours: []Contour{
+		{
+			Line{Vec{0, 0}, Vec{10, 0}},
+			Quadratic{Vec{10, 0}, Vec{15, 5}, Vec{10, 10}},
+			Cubic{Vec{10, 10}, Vec{5, 15}, Vec{2, 12}, Vec{0, 10}},
+			Line{Vec{0, 10}, Vec{0, 0}},
+		},
+		{
+			Line{Vec{2, 2}, Vec{3, 2}},
+			Line{Vec{3, 2}, Vec{3, 3}},
+			Line{Vec{3, 3}, Vec{2, 2}},
+		},
+	}}
+
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("Parsed shape should be %#v, not %#v", expected, s)
+	}
+}
+
+func TestParseShapeErrors(t *testing.T) {
+	for _, desc := range []string{
+		"0, 0; 1, 1",
+		"{ 0, 0; 1, 1",
+		"{ 0 0; 1, 1 }",
+		"{ 0, 0; (1, 1; 2, 2; 3, 3); 4, 4 }",
+		"{ }",
+		"{ 1, 1 }",
+	} {
+		if _, err := ParseShape(desc); err == nil {
+			t.Errorf("Parsing %q should return an error", desc)
+		}
+	}
+}
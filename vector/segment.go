@@ -0,0 +1,308 @@
+package vector
+
+import (
+	"math"
+	"sort"
+)
+
+// Segment is a parametric curve from t=0 to t=1 forming part of a Contour
+type Segment interface {
+	// Point returns the point at parameter t along the segment
+	Point(t float64) Vec
+	// Direction returns the tangent (the first derivative) at parameter t along the segment
+	Direction(t float64) Vec
+	// Nearest returns the parameter t in range [0, 1] of the point on the segment nearest to p
+	Nearest(p Vec) float64
+	// Split divides the segment in two at parameter t
+	Split(t float64) (Segment, Segment)
+	// Reverse returns the same segment traversed in the opposite direction
+	Reverse() Segment
+	// Transform returns the segment with its control points mapped by the affine function f
+	Transform(f func(Vec) Vec) Segment
+
+	// extrema returns the parameters strictly within (0, 1) where the x or y coordinate turns
+	extrema(y bool) []float64
+}
+
+// Distance returns the distance from p to the nearest point on the segment
+func Distance(s Segment, p Vec) float64 {
+	return s.Point(s.Nearest(p)).Sub(p).Len()
+}
+
+// Line is a straight line segment from P0 to P1
+type Line struct {
+	P0, P1 Vec
+}
+
+// Point returns the point at parameter t along the line
+func (l Line) Point(t float64) Vec {
+	return l.P0.Lerp(l.P1, t)
+}
+
+// Direction returns the direction of the line, which is the same at every t
+func (l Line) Direction(t float64) Vec {
+	return l.P1.Sub(l.P0)
+}
+
+// Nearest returns the parameter t in range [0, 1] of the point on the line nearest to p
+func (l Line) Nearest(p Vec) float64 {
+	ab := l.P1.Sub(l.P0)
+	lenSq := ab.Dot(ab)
+	if lenSq == 0 {
+		return 0
+	}
+	return clamp01(p.Sub(l.P0).Dot(ab) / lenSq)
+}
+
+// Split divides the line in two at parameter t
+func (l Line) Split(t float64) (Segment, Segment) {
+	m := l.Point(t)
+	return Line{l.P0, m}, Line{m, l.P1}
+}
+
+// Reverse returns the line from P1 to P0
+func (l Line) Reverse() Segment {
+	return Line{l.P1, l.P0}
+}
+
+// Transform returns the line with its end points mapped by f
+func (l Line) Transform(f func(Vec) Vec) Segment {
+	return Line{f(l.P0), f(l.P1)}
+}
+
+func (l Line) extrema(y bool) []float64 {
+	return nil
+}
+
+// Quadratic is a quadratic Bézier curve from P0 to P2 with control point P1
+type Quadratic struct {
+	P0, P1, P2 Vec
+}
+
+// Point returns the point at parameter t along the curve
+func (q Quadratic) Point(t float64) Vec {
+	mt := 1 - t
+	return q.P0.Scale(mt * mt).Add(q.P1.Scale(2 * mt * t)).Add(q.P2.Scale(t * t))
+}
+
+// Direction returns the tangent at parameter t along the curve
+func (q Quadratic) Direction(t float64) Vec {
+	d := q.P1.Sub(q.P0).Lerp(q.P2.Sub(q.P1), t).Scale(2)
+	if d == (Vec{}) {
+		// degenerate at an end point, so fall back to the chord
+		return q.P2.Sub(q.P0)
+	}
+	return d
+}
+
+// Nearest returns the parameter t in range [0, 1] of the point on the curve nearest to p.
+// The solution is exact, being a root of the cubic derivative of the squared distance.
+func (q Quadratic) Nearest(p Vec) float64 {
+	a := q.P1.Sub(q.P0)
+	b := q.P2.Sub(q.P1.Scale(2)).Add(q.P0)
+	c := q.P0.Sub(p)
+
+	candidates := append(solveCubic(b.Dot(b), 3*a.Dot(b), 2*a.Dot(a)+c.Dot(b), c.Dot(a)), 0, 1)
+	return nearestOf(q, p, candidates)
+}
+
+// Split divides the curve in two at parameter t
+func (q Quadratic) Split(t float64) (Segment, Segment) {
+	a := q.P0.Lerp(q.P1, t)
+	b := q.P1.Lerp(q.P2, t)
+	m := a.Lerp(b, t)
+	return Quadratic{q.P0, a, m}, Quadratic{m, b, q.P2}
+}
+
+// Reverse returns the curve from P2 to P0
+func (q Quadratic) Reverse() Segment {
+	return Quadratic{q.P2, q.P1, q.P0}
+}
+
+// Transform returns the curve with its control points mapped by f
+func (q Quadratic) Transform(f func(Vec) Vec) Segment {
+	return Quadratic{f(q.P0), f(q.P1), f(q.P2)}
+}
+
+func (q Quadratic) extrema(y bool) []float64 {
+	p0, p1, p2 := axis(q.P0, y), axis(q.P1, y), axis(q.P2, y)
+	return within01(solveQuadratic(0, 2*(p2-2*p1+p0), 2*(p1-p0)))
+}
+
+// Cubic is a cubic Bézier curve from P0 to P3 with control points P1 and P2
+type Cubic struct {
+	P0, P1, P2, P3 Vec
+}
+
+// Point returns the point at parameter t along the curve
+func (c Cubic) Point(t float64) Vec {
+	mt := 1 - t
+	return c.P0.Scale(mt * mt * mt).
+		Add(c.P1.Scale(3 * mt * mt * t)).
+		Add(c.P2.Scale(3 * mt * t * t)).
+		Add(c.P3.Scale(t * t * t))
+}
+
+// Direction returns the tangent at parameter t along the curve
+func (c Cubic) Direction(t float64) Vec {
+	mt := 1 - t
+	d := c.P1.Sub(c.P0).Scale(3 * mt * mt).
+		Add(c.P2.Sub(c.P1).Scale(6 * mt * t)).
+		Add(c.P3.Sub(c.P2).Scale(3 * t * t))
+	if d == (Vec{}) {
+		// degenerate at an end point, so fall back to the next control point along
+		if t < 0.5 {
+			return c.P2.Sub(c.P0)
+		}
+		return c.P3.Sub(c.P1)
+	}
+	return d
+}
+
+// secondDerivative returns the second derivative at parameter t along the curve
+func (c Cubic) secondDerivative(t float64) Vec {
+	a := c.P2.Sub(c.P1.Scale(2)).Add(c.P0)
+	b := c.P3.Sub(c.P2.Scale(2)).Add(c.P1)
+	return a.Lerp(b, t).Scale(6)
+}
+
+// Nearest returns the parameter t in range [0, 1] of the point on the curve nearest to p.
+// There is no closed form, so Newton's method is started from several points along the curve.
+func (c Cubic) Nearest(p Vec) float64 {
+	const starts = 8
+	const steps = 8
+
+	candidates := []float64{0, 1}
+	for i := 0; i <= starts; i++ {
+		t := float64(i) / starts
+		for j := 0; j < steps; j++ {
+			qp := c.Point(t).Sub(p)
+			d1 := c.Direction(t)
+			d2 := c.secondDerivative(t)
+
+			denom := d1.Dot(d1) + qp.Dot(d2)
+			if denom == 0 {
+				break
+			}
+			t = clamp01(t - qp.Dot(d1)/denom)
+		}
+		candidates = append(candidates, t)
+	}
+
+	return nearestOf(c, p, candidates)
+}
+
+// Split divides the curve in two at parameter t
+func (c Cubic) Split(t float64) (Segment, Segment) {
+	a := c.P0.Lerp(c.P1, t)
+	b := c.P1.Lerp(c.P2, t)
+	d := c.P2.Lerp(c.P3, t)
+	ab := a.Lerp(b, t)
+	bd := b.Lerp(d, t)
+	m := ab.Lerp(bd, t)
+	return Cubic{c.P0, a, ab, m}, Cubic{m, bd, d, c.P3}
+}
+
+// Reverse returns the curve from P3 to P0
+func (c Cubic) Reverse() Segment {
+	return Cubic{c.P3, c.P2, c.P1, c.P0}
+}
+
+// Transform returns the curve with its control points mapped by f
+func (c Cubic) Transform(f func(Vec) Vec) Segment {
+	return Cubic{f(c.P0), f(c.P1), f(c.P2), f(c.P3)}
+}
+
+func (c Cubic) extrema(y bool) []float64 {
+	p0, p1, p2, p3 := axis(c.P0, y), axis(c.P1, y), axis(c.P2, y), axis(c.P3, y)
+	a := -p0 + 3*p1 - 3*p2 + p3
+	b := 2 * (p0 - 2*p1 + p2)
+	d := p1 - p0
+	return within01(solveQuadratic(3*a, 3*b, 3*d))
+}
+
+// nearestOf returns whichever of the candidate parameters is nearest to p
+func nearestOf(s Segment, p Vec, candidates []float64) float64 {
+	best, bestDstSq := 0.0, math.Inf(1)
+	for _, t := range candidates {
+		if t < 0 || t > 1 {
+			continue
+		}
+		d := s.Point(t).Sub(p)
+		if dstSq := d.Dot(d); dstSq < bestDstSq {
+			best, bestDstSq = t, dstSq
+		}
+	}
+	return best
+}
+
+// crossings returns the signed number of times the segment crosses the horizontal ray
+// from p towards +x, with downward (+y) crossings counting +1 and upward ones -1.
+// Each y-monotonic piece of the segment is treated as half-open so that the crossings of
+// adjacent pieces and segments sharing an end point are never counted twice.
+func crossings(s Segment, p Vec) int {
+	ts := append([]float64{0}, s.extrema(true)...)
+	ts = append(ts, 1)
+	sort.Float64s(ts)
+
+	n := 0
+	for i := 1; i < len(ts); i++ {
+		t0, t1 := ts[i-1], ts[i]
+		y0, y1 := s.Point(t0).Y, s.Point(t1).Y
+
+		var dir int
+		switch {
+		case y0 <= p.Y && p.Y < y1:
+			dir = 1
+		case y1 <= p.Y && p.Y < y0:
+			dir = -1
+		default:
+			continue
+		}
+
+		if l, ok := s.(Line); ok {
+			if l.P0.X+(p.Y-y0)/(y1-y0)*(l.P1.X-l.P0.X) > p.X {
+				n += dir
+			}
+			continue
+		}
+
+		// bisect the monotonic piece to find where it crosses the ray
+		lo, hi := t0, t1
+		for j := 0; j < 52 && hi-lo > 1e-15; j++ {
+			mid := (lo + hi) / 2
+			if (s.Point(mid).Y < p.Y) == (y0 < y1) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+
+		if s.Point((lo+hi)/2).X > p.X {
+			n += dir
+		}
+	}
+
+	return n
+}
+
+func axis(v Vec, y bool) float64 {
+	if y {
+		return v.Y
+	}
+	return v.X
+}
+
+func within01(ts []float64) []float64 {
+	ret := []float64{}
+	for _, t := range ts {
+		if t > 0 && t < 1 {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+func clamp01(t float64) float64 {
+	return math.Max(0, math.Min(1, t))
+}
@@ -0,0 +1,103 @@
+package vector
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var testSegments = []Segment{
+	Line{Vec{1, 2}, Vec{7, -3}},
+	Quadratic{Vec{0, 0}, Vec{5, 10}, Vec{10, 0}},
+	Quadratic{Vec{-2, 3}, Vec{4, 4}, Vec{1, -6}},
+	Cubic{Vec{0, 0}, Vec{0, 10}, Vec{10, 10}, Vec{10, 0}},
+	Cubic{Vec{0, 0}, Vec{12, 8}, Vec{-4, 8}, Vec{8, 0}}, // self-intersecting loop
+}
+
+// sampledDistance approximates the distance from p to s by densely sampling it
+func sampledDistance(s Segment, p Vec) float64 {
+	min := math.Inf(1)
+	for i := 0; i <= 20000; i++ {
+		min = math.Min(min, s.Point(float64(i)/20000).Sub(p).Len())
+	}
+	return min
+}
+
+func TestSegmentNearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, s := range testSegments {
+		for i := 0; i < 200; i++ {
+			p := Vec{rng.Float64()*24 - 8, rng.Float64()*24 - 10}
+
+			d := Distance(s, p)
+			expected := sampledDistance(s, p)
+			if d > expected+1e-9 || expected-d > 1e-3 {
+				t.Errorf("%#v: distance to %v should be about %v, not %v", s, p, expected, d)
+			}
+		}
+	}
+}
+
+func TestSegmentSplit(t *testing.T) {
+	for _, s := range testSegments {
+		a, b := s.Split(0.3)
+
+		for _, tt := range []float64{0, 0.25, 0.5, 1} {
+			if d := a.Point(tt).Sub(s.Point(tt * 0.3)).Len(); d > 1e-9 {
+				t.Errorf("%#v: first half of split should match the original at %v, but is %v away", s, tt, d)
+			}
+			if d := b.Point(tt).Sub(s.Point(0.3 + tt*0.7)).Len(); d > 1e-9 {
+				t.Errorf("%#v: second half of split should match the original at %v, but is %v away", s, tt, d)
+			}
+		}
+	}
+}
+
+func TestSegmentReverse(t *testing.T) {
+	for _, s := range testSegments {
+		r := s.Reverse()
+		for _, tt := range []float64{0, 0.25, 0.5, 1} {
+			if d := r.Point(tt).Sub(s.Point(1 - tt)).Len(); d > 1e-9 {
+				t.Errorf("%#v: reversed segment should match the original at %v, but is %v away", s, 1-tt, d)
+			}
+		}
+	}
+}
+
+func TestSegmentDirection(t *testing.T) {
+	for _, s := range testSegments {
+		for _, tt := range []float64{0.1, 0.5, 0.9} {
+			const h = 1e-6
+			numeric := s.Point(tt + h).Sub(s.Point(tt - h)).Scale(1 / (2 * h))
+			if d := numeric.Sub(s.Direction(tt)).Len(); d > 1e-4 {
+				t.Errorf("%#v: direction at %v should be %v, not %v", s, tt, numeric, s.Direction(tt))
+			}
+		}
+	}
+}
+
+func TestSolveCubic(t *testing.T) {
+	tests := []struct {
+		a, b, c, d float64
+		roots      []float64
+	}{
+		{1, -6, 11, -6, []float64{1, 2, 3}},
+		{1, 0, 0, -8, []float64{2}},
+		{0, 1, -3, 2, []float64{1, 2}},
+		{0, 0, 2, -1, []float64{0.5}},
+	}
+
+	for _, tt := range tests {
+		roots := solveCubic(tt.a, tt.b, tt.c, tt.d)
+		for _, expected := range tt.roots {
+			found := false
+			for _, r := range roots {
+				found = found || math.Abs(r-expected) < 1e-9
+			}
+			if !found {
+				t.Errorf("Roots of %vx^3 + %vx^2 + %vx + %v should include %v, not just %v", tt.a, tt.b, tt.c, tt.d, expected, roots)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package vector
+
+import "math"
+
+// Contour is a closed loop of segments, each starting where the previous one ends
+// and the last ending where the first starts
+type Contour []Segment
+
+// FillRule defines which areas enclosed by the contours of a Shape are inside it
+type FillRule int
+
+const (
+	// NonZero fills areas the contours wind around a non-zero number of times. This is the default.
+	NonZero FillRule = iota
+	// EvenOdd fills areas the contours wind around an odd number of times
+	EvenOdd
+)
+
+// Shape is a set of contours whose interior is defined by the fill rule
+type Shape struct {
+	Contours []Contour
+	FillRule FillRule
+}
+
+// Winding returns the winding number of the shape around p
+func (s *Shape) Winding(p Vec) int {
+	n := 0
+	for _, c := range s.Contours {
+		for _, seg := range c {
+			n += crossings(seg, p)
+		}
+	}
+	return n
+}
+
+// Contains predicates whether p is inside the shape
+func (s *Shape) Contains(p Vec) bool {
+	if s.FillRule == EvenOdd {
+		return s.Winding(p)%2 != 0
+	}
+	return s.Winding(p) != 0
+}
+
+// Distance returns the unsigned distance from p to the nearest point on the outline of the shape
+func (s *Shape) Distance(p Vec) float64 {
+	min := math.Inf(1)
+	for _, c := range s.Contours {
+		for _, seg := range c {
+			min = math.Min(min, Distance(seg, p))
+		}
+	}
+	return min
+}
+
+// SignedDistance returns the distance from p to the outline of the shape,
+// which is negative if p is inside the shape and positive if outside
+func (s *Shape) SignedDistance(p Vec) float64 {
+	d := s.Distance(p)
+	if s.Contains(p) {
+		return -d
+	}
+	return d
+}
+
+// Bounds returns the top-left and bottom-right corners of the smallest rectangle containing the shape
+func (s *Shape) Bounds() (Vec, Vec) {
+	min := Vec{math.Inf(1), math.Inf(1)}
+	max := Vec{math.Inf(-1), math.Inf(-1)}
+
+	include := func(p Vec) {
+		min = Vec{math.Min(min.X, p.X), math.Min(min.Y, p.Y)}
+		max = Vec{math.Max(max.X, p.X), math.Max(max.Y, p.Y)}
+	}
+
+	for _, c := range s.Contours {
+		for _, seg := range c {
+			include(seg.Point(0))
+			for _, t := range seg.extrema(false) {
+				include(seg.Point(t))
+			}
+			for _, t := range seg.extrema(true) {
+				include(seg.Point(t))
+			}
+		}
+	}
+
+	return min, max
+}
+
+// Transform returns a copy of the shape with every segment mapped by the affine function f
+func (s *Shape) Transform(f func(Vec) Vec) *Shape {
+	ret := &Shape{Contours: make([]Contour, len(s.Contours)), FillRule: s.FillRule}
+	for i, c := range s.Contours {
+		ret.Contours[i] = make(Contour, len(c))
+		for j, seg := range c {
+			ret.Contours[i][j] = seg.Transform(f)
+		}
+	}
+	return ret
+}
+
+// Reverse returns the contour traversed in the opposite direction
+func (c Contour) Reverse() Contour {
+	ret := make(Contour, len(c))
+	for i, seg := range c {
+		ret[len(c)-1-i] = seg.Reverse()
+	}
+	return ret
+}
+
+// Orient reverses any contours as necessary so that the interior of the shape lies to the
+// right-hand side of every segment when travelling along it (with y pointing down), such that
+// Direction(t).Cross(p - Point(t)) is positive for points p just inside the shape. This is the
+// orientation expected when using the side of a segment to sign distances.
+//
+// Where contours do not cross one another the area covered by the shape is unchanged.
+func (s *Shape) Orient() {
+	// probe the shape as it was originally, as reversing contours can alter the winding numbers
+	original := &Shape{Contours: append([]Contour{}, s.Contours...), FillRule: s.FillRule}
+
+	for i, c := range s.Contours {
+		if len(c) == 0 {
+			continue
+		}
+
+		// probe either side of the middle of the longest segment as it is least likely to be
+		// confused by other nearby segments
+		seg := c[0]
+		longest := 0.0
+		for _, candidate := range c {
+			if l := candidate.Point(1).Sub(candidate.Point(0)).Len(); l > longest {
+				seg, longest = candidate, l
+			}
+		}
+
+		mid := seg.Point(0.5)
+		dir := seg.Direction(0.5).Normalize()
+		right := Vec{-dir.Y, dir.X}
+		eps := math.Max(1e-6, longest*1e-4)
+
+		if !original.Contains(mid.Add(right.Scale(eps))) && original.Contains(mid.Sub(right.Scale(eps))) {
+			s.Contours[i] = c.Reverse()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+// circle returns a circle approximated by 4 cubic Béziers
+func circle(cx, cy, r float64) Contour {
+	k := r * 0.5522847498
+	return Contour{
+		Cubic{Vec{cx + r, cy}, Vec{cx + r, cy + k}, Vec{cx + k, cy + r}, Vec{cx, cy + r}},
+		Cubic{Vec{cx, cy + r}, Vec{cx - k, cy + r}, Vec{cx - r, cy + k}, Vec{cx - r, cy}},
+		Cubic{Vec{cx - r, cy}, Vec{cx - r, cy - k}, Vec{cx - k, cy - r}, Vec{cx, cy - r}},
+		Cubic{Vec{cx, cy - r}, Vec{cx + k, cy - r}, Vec{cx + r, cy - k}, Vec{cx + r, cy}},
+	}
+}
+
+func square(x0, y0, x1, y1 float64) Contour {
+	return Contour{
+		Line{Vec{x0, y0}, Vec{x1, y0}},
+		Line{Vec{x1, y0}, Vec{x1, y1}},
+		Line{Vec{x1, y1}, Vec{x0, y1}},
+		Line{Vec{x0, y1}, Vec{x0, y0}},
+	}
+}
+
+func TestShapeContains(t *testing.T) {
+	// a square with a circular hole wound in the opposite direction
+	s := &Shape{Contours: []Contour{square(0, 0, 10, 10), circle(5, 5, 3).Reverse()}}
+
+	tests := []struct {
+		p      Vec
+		inside bool
+	}{
+		{Vec{1, 1}, true},
+		{Vec{5, 5}, false},
+		{Vec{5, 1.5}, true},
+		{Vec{5, 2.5}, false},
+		{Vec{11, 5}, false},
+		{Vec{-1, 5}, false},
+		// rays passing exactly through vertices must not be double counted
+		{Vec{-1, 0}, false},
+		{Vec{5, 0.5}, true},
+		{Vec{1, 5}, true},
+	}
+
+	for _, tt := range tests {
+		if s.Contains(tt.p) != tt.inside {
+			t.Errorf("Contains(%v) should be %v", tt.p, tt.inside)
+		}
+	}
+}
+
+func TestShapeSignedDistance(t *testing.T) {
+	s := &Shape{Contours: []Contour{circle(5, 5, 3)}}
+
+	for _, p := range []Vec{{5, 5}, {5, 1}, {9, 9}, {6.5, 5.5}} {
+		expected := p.Sub(Vec{5, 5}).Len() - 3
+		if d := s.SignedDistance(p); math.Abs(d-expected) > 0.01 {
+			t.Errorf("SignedDistance(%v) should be about %v, not %v", p, expected, d)
+		}
+	}
+}
+
+func TestShapeBounds(t *testing.T) {
+	s := &Shape{Contours: []Contour{circle(5, 6, 3)}}
+	min, max := s.Bounds()
+
+	if math.Abs(min.X-2) > 1e-9 || math.Abs(min.Y-3) > 1e-9 || math.Abs(max.X-8) > 1e-9 || math.Abs(max.Y-9) > 1e-9 {
+		t.Errorf("Bounds should be (2, 3)-(8, 9), not %v-%v", min, max)
+	}
+}
+
+func TestShapeOrient(t *testing.T) {
+	s := &Shape{Contours: []Contour{square(0, 0, 10, 10).Reverse(), circle(5, 5, 3).Reverse()}}
+	s.Orient()
+
+	for i, c := range s.Contours {
+		for _, seg := range c {
+			dir := seg.Direction(0.5).Normalize()
+			p := seg.Point(0.5).Add(Vec{-dir.Y, dir.X}.Scale(0.01))
+			if !s.Contains(p) {
+				t.Errorf("Contour %v should be oriented with the interior to the right of %#v", i, seg)
+			}
+		}
+	}
+}
+
+func TestShapeTransform(t *testing.T) {
+	s := &Shape{Contours: []Contour{square(0, 0, 1, 2)}}
+	scaled := s.Transform(func(v Vec) Vec { return v.Scale(3).Add(Vec{1, 1}) })
+
+	min, max := scaled.Bounds()
+	if min != (Vec{1, 1}) || max != (Vec{4, 7}) {
+		t.Errorf("Transformed bounds should be (1, 1)-(4, 7), not %v-%v", min, max)
+	}
+}
@@ -0,0 +1,148 @@
+package vector
+
+import (
+	"container/heap"
+	"math"
+)
+
+// SimplifyDouglasPeucker returns the polyline with as few of its points as the Douglas-Peucker
+// algorithm needs to keep every removed point within tolerance of the simplified polyline
+func (p Polyline) SimplifyDouglasPeucker(tolerance float64) Polyline {
+	if len(p) < 4 {
+		return p
+	}
+
+	// a closed polyline is split in two at the point furthest from its first point, each half
+	// then being simplified as an open polyline between fixed ends
+	far, dist := 0, 0.0
+	for i, v := range p {
+		if d := v.Sub(p[0]).Len(); d > dist {
+			far, dist = i, d
+		}
+	}
+
+	keep := make([]bool, len(p))
+	keep[0], keep[far] = true, true
+	closed := append(append(Polyline{}, p...), p[0])
+	douglasPeucker(closed, 0, far, tolerance, keep)
+	douglasPeucker(closed, far, len(p), tolerance, keep)
+
+	var ret Polyline
+	for i, v := range p {
+		if keep[i] {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// douglasPeucker marks the points to keep between the fixed points at indices i and j
+func douglasPeucker(p Polyline, i, j int, tolerance float64, keep []bool) {
+	if j-i < 2 {
+		return
+	}
+
+	chord := Line{p[i], p[j]}
+	far, dist := 0, -1.0
+	for k := i + 1; k < j; k++ {
+		if d := Distance(chord, p[k]); d > dist {
+			far, dist = k, d
+		}
+	}
+	if dist <= tolerance {
+		return
+	}
+
+	keep[far%len(keep)] = true
+	douglasPeucker(p, i, far, tolerance, keep)
+	douglasPeucker(p, far, j, tolerance, keep)
+}
+
+// SimplifyVisvalingam returns the polyline simplified by the Visvalingam-Whyatt algorithm, which
+// repeatedly removes the point that forms the triangle of least area with its neighbours, until
+// every triangle has an area of at least tolerance squared. This keeps the overall shape of
+// gentle curves better than SimplifyDouglasPeucker, which favours keeping sharp spikes.
+func (p Polyline) SimplifyVisvalingam(tolerance float64) Polyline {
+	if len(p) < 4 {
+		return p
+	}
+
+	// the points form a circular linked list, ordered in a heap by the area of their triangles
+	points := make([]*vwPoint, len(p))
+	for i := range p {
+		points[i] = &vwPoint{index: i}
+	}
+	for i, pt := range points {
+		pt.prev = points[(i+len(p)-1)%len(p)]
+		pt.next = points[(i+1)%len(p)]
+	}
+
+	h := make(vwHeap, len(p))
+	for i, pt := range points {
+		pt.area = pt.triangle(p)
+		pt.heapIndex = i
+		h[i] = pt
+	}
+	heap.Init(&h)
+
+	minArea := tolerance * tolerance
+	remaining := len(p)
+	for remaining > 3 && h[0].area < minArea {
+		pt := heap.Pop(&h).(*vwPoint)
+		pt.removed = true
+		remaining--
+
+		pt.prev.next, pt.next.prev = pt.next, pt.prev
+		for _, n := range []*vwPoint{pt.prev, pt.next} {
+			n.area = n.triangle(p)
+			heap.Fix(&h, n.heapIndex)
+		}
+	}
+
+	var ret Polyline
+	for i, pt := range points {
+		if !pt.removed {
+			ret = append(ret, p[i])
+		}
+	}
+	return ret
+}
+
+// vwPoint is a point of a polyline being simplified by SimplifyVisvalingam
+type vwPoint struct {
+	index      int
+	prev, next *vwPoint
+	area       float64
+	heapIndex  int
+	removed    bool
+}
+
+// triangle returns the area of the triangle formed by the point and its neighbours
+func (pt *vwPoint) triangle(p Polyline) float64 {
+	a, b, c := p[pt.prev.index], p[pt.index], p[pt.next.index]
+	return math.Abs(b.Sub(a).Cross(c.Sub(a))) / 2
+}
+
+// vwHeap is a min-heap of points by area, implementing heap.Interface
+type vwHeap []*vwPoint
+
+func (h vwHeap) Len() int           { return len(h) }
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex, h[j].heapIndex = i, j
+}
+
+func (h *vwHeap) Push(x interface{}) {
+	pt := x.(*vwPoint)
+	pt.heapIndex = len(*h)
+	*h = append(*h, pt)
+}
+
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	pt := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return pt
+}
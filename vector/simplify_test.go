@@ -0,0 +1,66 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+// noisySquare returns a square polyline of side 10 with many points along each side, each
+// displaced from it by up to 0.1
+func noisySquare() Polyline {
+	var p Polyline
+	corners := []Vec{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	for i, a := range corners {
+		b := corners[(i+1)%4]
+		normal := Vec{a.Y - b.Y, b.X - a.X}.Normalize()
+		for k := 0; k < 20; k++ {
+			p = append(p, a.Lerp(b, float64(k)/20).Add(normal.Scale(0.1*math.Sin(float64(k)))))
+		}
+	}
+	return p
+}
+
+// hasPoint predicates whether the polyline holds a point within 0.2 of v
+func hasPoint(p Polyline, v Vec) bool {
+	for _, u := range p {
+		if u.Sub(v).Len() < 0.2 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSimplify(t *testing.T) {
+	p := noisySquare()
+	// noise along a long side forms triangles of larger area than its distance from the side
+	for name, simplified := range map[string]Polyline{
+		"Douglas-Peucker": p.SimplifyDouglasPeucker(0.5),
+		"Visvalingam":     p.SimplifyVisvalingam(1.5),
+	} {
+		if len(simplified) != 4 {
+			t.Errorf("%v should simplify the square to 4 points, not %v", name, simplified)
+		}
+		for _, corner := range []Vec{{0, 0}, {10, 0}, {10, 10}, {0, 10}} {
+			if !hasPoint(simplified, corner) {
+				t.Errorf("%v should keep the corner %v, not be %v", name, corner, simplified)
+			}
+		}
+		if a := simplified.Area(); math.Abs(a-100) > 2 {
+			t.Errorf("%v should keep the area of about 100, not %v", name, a)
+		}
+	}
+}
+
+func TestSimplifyKeepsDetail(t *testing.T) {
+	// a notch deeper than the tolerance survives
+	p := Polyline{{0, 0}, {4, 0}, {5, 2}, {6, 0}, {10, 0}, {10, 10}, {0, 10}}
+	if s := p.SimplifyDouglasPeucker(1); !hasPoint(s, Vec{5, 2}) {
+		t.Errorf("Douglas-Peucker should keep the notch, not be %v", s)
+	}
+	if s := p.SimplifyVisvalingam(1); !hasPoint(s, Vec{5, 2}) {
+		t.Errorf("Visvalingam should keep the notch, not be %v", s)
+	}
+	if s := p.SimplifyDouglasPeucker(3); hasPoint(s, Vec{5, 2}) {
+		t.Errorf("Douglas-Peucker should remove the notch within tolerance, not be %v", s)
+	}
+}
@@ -0,0 +1,56 @@
+package vector
+
+import "math"
+
+// solveQuadratic returns the real roots of a*x^2 + b*x + c = 0
+func solveQuadratic(a, b, c float64) []float64 {
+	if math.Abs(a) < 1e-12 {
+		if math.Abs(b) < 1e-12 {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+
+	disc := b*b - 4*a*c
+	switch {
+	case disc > 0:
+		// avoid cancellation by computing the larger-magnitude root first
+		q := -0.5 * (b + math.Copysign(math.Sqrt(disc), b))
+		return []float64{q / a, c / q}
+	case disc == 0:
+		return []float64{-b / (2 * a)}
+	}
+	return nil
+}
+
+// solveCubic returns the real roots of a*x^3 + b*x^2 + c*x + d = 0
+func solveCubic(a, b, c, d float64) []float64 {
+	if math.Abs(a) < 1e-12 {
+		return solveQuadratic(b, c, d)
+	}
+
+	// reduce to the depressed cubic t^3 + p*t + q = 0 with x = t - b/3a
+	b, c, d = b/a, c/a, d/a
+	p := c - b*b/3
+	q := 2*b*b*b/27 - b*c/3 + d
+	shift := -b / 3
+
+	disc := q*q/4 + p*p*p/27
+	switch {
+	case disc > 0:
+		s := math.Sqrt(disc)
+		return []float64{math.Cbrt(-q/2+s) + math.Cbrt(-q/2-s) + shift}
+	case disc == 0:
+		u := math.Cbrt(-q / 2)
+		return []float64{2*u + shift, -u + shift}
+	}
+
+	// three distinct real roots, found trigonometrically
+	r := 2 * math.Sqrt(-p/3)
+	phi := math.Acos(math.Max(-1, math.Min(1, 3*q/(p*r))))
+	return []float64{
+		r*math.Cos(phi/3) + shift,
+		r*math.Cos((phi+2*math.Pi)/3) + shift,
+		r*math.Cos((phi+4*math.Pi)/3) + shift,
+	}
+}
@@ -0,0 +1,27 @@
+package vector
+
+import "math"
+
+// Stencil implements an sdf.Stencil by sampling a Region, such as a Shape or a Union, at the
+// centre of each pixel
+type Stencil struct {
+	Shape  Region
+	Width  int
+	Height int
+}
+
+// Within predicates whether the centre of the given pixel is inside the shape
+func (s Stencil) Within(x, y int) bool {
+	return s.Shape.Contains(pixelCentre(x, y))
+}
+
+// Coverage estimates the fraction of the given pixel covered by the shape from the exact
+// distance to the outline, treating the outline as a straight edge across the pixel
+func (s Stencil) Coverage(x, y int) float64 {
+	return math.Max(0, math.Min(1, 0.5-s.Shape.SignedDistance(pixelCentre(x, y))))
+}
+
+// Size returns the width and height of the Stencil
+func (s Stencil) Size() (int, int) {
+	return s.Width, s.Height
+}
@@ -0,0 +1,61 @@
+package vector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+var _ sdf.CoverageStencil = Stencil{}
+
+func TestStencil(t *testing.T) {
+	s := Stencil{
+		Shape:  &Shape{Contours: []Contour{square(1, 1, 3, 4)}},
+		Width:  5,
+		Height: 6,
+	}
+
+	w, h := s.Size()
+	if w != 5 || h != 6 {
+		t.Errorf("Stencil width and height should be (5, 6), not (%v, %v)", w, h)
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			expected := x >= 1 && x < 3 && y >= 1 && y < 4
+			if s.Within(x, y) != expected {
+				t.Errorf("Stencil at (%v, %v) should be within = %v", x, y, expected)
+			}
+		}
+	}
+}
+
+func TestStencilCoverage(t *testing.T) {
+	// a vertical edge at x=2.25 covers a quarter of column 2
+	s := Stencil{Shape: &Shape{Contours: []Contour{square(0, 0, 2.25, 4)}}, Width: 4, Height: 4}
+
+	expected := []float64{1, 1, 0.25, 0}
+	for x, e := range expected {
+		if c := s.Coverage(x, 2); math.Abs(c-e) > 1e-9 {
+			t.Errorf("Coverage at (%v, 2) should be %v, not %v", x, e, c)
+		}
+	}
+}
+
+func TestShapeEvenOdd(t *testing.T) {
+	// two squares wound the same way, one inside the other
+	s := &Shape{Contours: []Contour{square(0, 0, 10, 10), square(3, 3, 7, 7)}}
+
+	if !s.Contains(Vec{5, 5}) {
+		t.Errorf("NonZero fill should contain the doubly wound centre")
+	}
+
+	s.FillRule = EvenOdd
+	if s.Contains(Vec{5, 5}) {
+		t.Errorf("EvenOdd fill should not contain the doubly wound centre")
+	}
+	if !s.Contains(Vec{1, 5}) {
+		t.Errorf("EvenOdd fill should contain the singly wound border")
+	}
+}
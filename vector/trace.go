@@ -0,0 +1,183 @@
+package vector
+
+import (
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// Polyline is a closed loop of points, the last joining back to the first
+type Polyline []Vec
+
+// Area returns the signed area enclosed by the polyline, which is positive when the enclosed area
+// lies to the right-hand side of travel (with y pointing down), as for the contours of an
+// oriented Shape, and negative when it lies to the left.
+func (p Polyline) Area() float64 {
+	area := 0.0
+	for i, a := range p {
+		b := p[(i+1)%len(p)]
+		area += a.Cross(b)
+	}
+	return area / 2
+}
+
+// Contour returns the polyline as a contour of line segments
+func (p Polyline) Contour() Contour {
+	c := make(Contour, len(p))
+	for i, a := range p {
+		c[i] = Line{a, p[(i+1)%len(p)]}
+	}
+	return c
+}
+
+// Trace extracts the iso-line of the field at the distance iso using marching squares, returning
+// it as closed polylines whose vertices are interpolated between the pixel centres either side
+// of the line. Trace(field, 0) traces the edge of the field's shape, while other values trace
+// the edge grown (iso > 0) or shrunk (iso < 0) by that distance.
+//
+// Polylines are oriented as the contours of an oriented Shape, so that the inside (distances
+// no greater than iso) lies to the right-hand side of travel. Outer boundaries therefore have a
+// positive Area and the boundaries of holes a negative one. Everything beyond the edges of the
+// field is outside, so polylines are closed through the outermost pixel centres.
+//
+// Where a cell of four pixels has its inside corners diagonally opposite one another, the value
+// at the centre of the cell decides whether they are joined by the inside or separated by the
+// outside.
+func Trace(field *sdf.SDF, iso float64) []Polyline {
+	t := tracer{field: field, iso: iso, next: map[crossing]crossing{}, at: map[crossing]Vec{}}
+
+	// cells lie between four pixel centres, including a ring of cells around the field that
+	// reach out to the virtual pixels beyond its edges
+	for y := -1; y < field.Height; y++ {
+		for x := -1; x < field.Width; x++ {
+			t.cell(x, y)
+		}
+	}
+
+	var lines []Polyline
+	visited := map[crossing]bool{}
+	for _, start := range t.starts {
+		if visited[start] {
+			continue
+		}
+
+		var line Polyline
+		for c := start; !visited[c]; c = t.next[c] {
+			visited[c] = true
+			p := t.at[c]
+			// crossings shared with the virtual pixels can land on the same pixel centre
+			if len(line) == 0 || line[len(line)-1] != p {
+				line = append(line, p)
+			}
+		}
+		if len(line) > 1 && line[len(line)-1] == line[0] {
+			line = line[:len(line)-1]
+		}
+		if len(line) > 2 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// crossing identifies the edge between a pixel centre and the next one to its right or below it
+type crossing struct {
+	x, y     int
+	vertical bool
+}
+
+type tracer struct {
+	field *sdf.SDF
+	iso   float64
+	// next links each crossing to the following one along its polyline
+	next map[crossing]crossing
+	at   map[crossing]Vec
+	// starts lists the crossings in the order found, so the output does not depend on map order
+	starts []crossing
+}
+
+// value returns the distance at a pixel centre, and whether it is a virtual pixel beyond the
+// edges of the field
+func (t *tracer) value(x, y int) (float64, bool) {
+	if x < 0 || y < 0 || x >= t.field.Width || y >= t.field.Height {
+		return math.Inf(1), true
+	}
+	return t.field.At(x, y), false
+}
+
+func (t *tracer) inside(x, y int) bool {
+	v, _ := t.value(x, y)
+	return v <= t.iso
+}
+
+// cell adds the segments of the cell whose top-left corner is the pixel centre at (x, y)
+func (t *tracer) cell(x, y int) {
+	// the corners and then the edges that run clockwise from each of them
+	corners := [4][2]int{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}}
+	edges := [4]crossing{{x, y, false}, {x + 1, y, true}, {x, y + 1, false}, {x, y, true}}
+
+	// clockwise around the cell, the edges where it passes out of the inside and into it
+	var found []crossing
+	var leaving []bool
+	for i, e := range edges {
+		a, b := corners[i], corners[(i+1)%4]
+		if in := t.inside(a[0], a[1]); in != t.inside(b[0], b[1]) {
+			found = append(found, e)
+			leaving = append(leaving, in)
+			t.at[e] = t.interpolate(a, b)
+		}
+	}
+	if len(found) == 0 {
+		return
+	}
+
+	// segments run from where the inside is left to where it is entered, keeping the inside on
+	// their right. Entering next joins the inside corners through the cell, while entering from
+	// the previous edge separates them, which only differs for saddles with four crossings.
+	joined := len(found) == 4 && t.centreInside(x, y)
+	for i, e := range found {
+		if !leaving[i] {
+			continue
+		}
+		end := found[(i+len(found)-1)%len(found)]
+		if joined {
+			end = found[(i+1)%len(found)]
+		}
+		t.next[e] = end
+		t.starts = append(t.starts, e)
+	}
+}
+
+// centreInside predicates whether the centre of a saddle cell is inside, taking the mean of its
+// corners as the value there
+func (t *tracer) centreInside(x, y int) bool {
+	sum := 0.0
+	for _, c := range [4][2]int{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}} {
+		v, _ := t.value(c[0], c[1])
+		sum += v
+	}
+	return sum/4 <= t.iso
+}
+
+// interpolate returns the point between two adjacent pixel centres at which the field crosses
+// the iso value. Crossings with virtual pixels lie on the pixel of the field.
+func (t *tracer) interpolate(a, b [2]int) Vec {
+	va, virtualA := t.value(a[0], a[1])
+	vb, virtualB := t.value(b[0], b[1])
+	pa, pb := pixelCentre(a[0], a[1]), pixelCentre(b[0], b[1])
+
+	switch {
+	case virtualA:
+		return pb
+	case virtualB:
+		return pa
+	}
+
+	s := (t.iso - va) / (vb - va)
+	if math.IsNaN(s) {
+		// a field with no boundary holds infinite distances and gives no position to go by
+		s = 0.5
+	}
+	return pa.Lerp(pb, math.Max(0, math.Min(1, s)))
+}
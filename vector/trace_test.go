@@ -0,0 +1,119 @@
+package vector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// gridField returns a field holding the given rows of values
+func gridField(rows [][]float64) *sdf.SDF {
+	f := sdf.New(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x, v := range row {
+			f.Set(x, y, v)
+		}
+	}
+	return f
+}
+
+func TestTraceCircle(t *testing.T) {
+	field := (&Shape{Contours: []Contour{circle(16, 16, 10)}}).Field(32, 32)
+
+	for _, iso := range []float64{0, 2.5, -3} {
+		lines := Trace(field, iso)
+		if len(lines) != 1 {
+			t.Fatalf("Circle should trace 1 polyline at %v, not %v", iso, len(lines))
+		}
+
+		// vertices are interpolated between pixel centres onto the circle of radius 10+iso
+		for _, p := range lines[0] {
+			if r := p.Sub(Vec{16, 16}).Len(); math.Abs(r-(10+iso)) > 0.05 {
+				t.Errorf("Vertex %v at %v should be about %v from the centre, not %v", p, iso, 10+iso, r)
+			}
+		}
+
+		if a, expected := lines[0].Area(), math.Pi*(10+iso)*(10+iso); math.Abs(a-expected)/expected > 0.01 {
+			t.Errorf("Area at %v should be about %v, not %v", iso, expected, a)
+		}
+	}
+}
+
+func TestTraceHole(t *testing.T) {
+	ring := &Shape{Contours: []Contour{circle(16, 16, 12), circle(16, 16, 6).Reverse()}}
+	lines := Trace(ring.Field(32, 32), 0)
+	if len(lines) != 2 {
+		t.Fatalf("Ring should trace 2 polylines, not %v", len(lines))
+	}
+
+	outer, hole := lines[0], lines[1]
+	if outer.Area() < 0 {
+		outer, hole = hole, outer
+	}
+	if a := outer.Area(); !(a > 400) {
+		t.Errorf("Outer boundary should have a large positive area, not %v", a)
+	}
+	if a := hole.Area(); !(a < -100 && a > -120) {
+		t.Errorf("Hole should have a negative area, not %v", a)
+	}
+
+	// the inside lies to the right of travel, as for an oriented shape
+	traced := &Shape{Contours: []Contour{outer.Contour(), hole.Contour()}}
+	oriented := &Shape{Contours: []Contour{outer.Contour(), hole.Contour()}}
+	oriented.Orient()
+	for i := range traced.Contours {
+		if traced.Contours[i][0] != oriented.Contours[i][0] {
+			t.Errorf("Polyline %v should already be oriented", i)
+		}
+	}
+}
+
+func TestTraceSaddle(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		field  [][]float64
+		expect int
+	}{
+		{"joined", [][]float64{{-1, 1}, {1, -1}}, 1},
+		{"separated", [][]float64{{-1, 2}, {2, -1}}, 2},
+	} {
+		lines := Trace(gridField(test.field), 0)
+		if len(lines) != test.expect {
+			t.Errorf("Saddle %v should trace %v polylines, not %v", test.name, test.expect, len(lines))
+		}
+		for _, line := range lines {
+			if a := line.Area(); !(a > 0) {
+				t.Errorf("Saddle %v should trace outer boundaries, not one of area %v", test.name, a)
+			}
+		}
+	}
+}
+
+func TestTraceEdges(t *testing.T) {
+	// a shape cut off by the edges of the field is closed through the outermost pixel centres
+	lines := Trace(gridField([][]float64{{-1, -1, 1}, {-1, -1, 1}, {1, 1, 1}}), 0)
+	if len(lines) != 1 {
+		t.Fatalf("Should trace 1 polyline, not %v", len(lines))
+	}
+
+	expected := []Vec{{0.5, 0.5}, {1.5, 0.5}, {2, 1.5}, {1.5, 2}, {0.5, 2}}
+	for _, p := range expected {
+		found := false
+		for _, v := range lines[0] {
+			found = found || v == p
+		}
+		if !found {
+			t.Errorf("Polyline should pass through %v, not be %v", p, lines[0])
+		}
+	}
+	if a := lines[0].Area(); !(a > 0) {
+		t.Errorf("Polyline should be an outer boundary, not have area %v", a)
+	}
+}
+
+func TestTraceEmpty(t *testing.T) {
+	if lines := Trace(gridField([][]float64{{1, 2}, {3, 4}}), 0); len(lines) != 0 {
+		t.Errorf("Field with no inside should trace nothing, not %v", lines)
+	}
+}
@@ -0,0 +1,81 @@
+package vector
+
+import (
+	"math"
+
+	"github.com/daveagill/go-sdf/sdf"
+)
+
+// Region is an area of the plane that can be queried exactly, such as a Shape or a Union
+type Region interface {
+	// Contains predicates whether p is inside the region
+	Contains(p Vec) bool
+	// SignedDistance returns the distance from p to the outline of the region, which is
+	// negative if p is inside the region and positive if outside
+	SignedDistance(p Vec) float64
+}
+
+// Union is the area covered by any of a set of shapes, each filled according to its own fill rule
+type Union []*Shape
+
+// Contains predicates whether p is inside any of the shapes
+func (u Union) Contains(p Vec) bool {
+	for _, s := range u {
+		if s.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedDistance returns the least of the signed distances from p to each of the shapes, as for
+// sdf.Union. An empty union is infinitely far away.
+func (u Union) SignedDistance(p Vec) float64 {
+	min := math.Inf(1)
+	for _, s := range u {
+		min = math.Min(min, s.SignedDistance(p))
+	}
+	return min
+}
+
+// Bounds returns the top-left and bottom-right corners of the smallest rectangle containing every
+// shape. An empty union has a top-left corner at +Inf and a bottom-right corner at -Inf.
+func (u Union) Bounds() (Vec, Vec) {
+	min := Vec{math.Inf(1), math.Inf(1)}
+	max := Vec{math.Inf(-1), math.Inf(-1)}
+	for _, s := range u {
+		lo, hi := s.Bounds()
+		min = Vec{math.Min(min.X, lo.X), math.Min(min.Y, lo.Y)}
+		max = Vec{math.Max(max.X, hi.X), math.Max(max.Y, hi.Y)}
+	}
+	return min, max
+}
+
+// Transform returns a copy of the union with every shape mapped by the affine function f
+func (u Union) Transform(f func(Vec) Vec) Union {
+	ret := make(Union, len(u))
+	for i, s := range u {
+		ret[i] = s.Transform(f)
+	}
+	return ret
+}
+
+// Field returns an SDF of the given size holding the signed distance from the centre of each
+// pixel to the union, as for Shape.Field
+func (u Union) Field(w, h int) *sdf.SDF {
+	return Bake(u.SignedDistance, w, h)
+}
+
+// Merge returns a single Shape, filled by the NonZero rule, holding the contours of every shape.
+// The contours of each shape are oriented first so that each winds once around the area it fills,
+// so the merged shape covers the same area as the union as long as no contour crosses another
+// contour of the same shape. This suits consumers that need one set of contours, such as msdf.
+func (u Union) Merge() *Shape {
+	merged := &Shape{FillRule: NonZero}
+	for _, s := range u {
+		oriented := s.Transform(func(v Vec) Vec { return v })
+		oriented.Orient()
+		merged.Contours = append(merged.Contours, oriented.Contours...)
+	}
+	return merged
+}
@@ -0,0 +1,44 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnion(t *testing.T) {
+	// a ring filled by EvenOdd with a square reaching into its hole, wound the other way round
+	ring := &Shape{Contours: []Contour{square(0, 0, 10, 10), square(2, 2, 8, 8)}, FillRule: EvenOdd}
+	block := &Shape{Contours: []Contour{square(5, 5, 15, 15).Reverse()}}
+	u := Union{ring, block}
+
+	for _, test := range []struct {
+		p      Vec
+		inside bool
+	}{
+		{Vec{1, 1}, true},
+		{Vec{3, 3}, false},
+		{Vec{6, 6}, true},
+		{Vec{12, 12}, true},
+		{Vec{12, 2}, false},
+	} {
+		if u.Contains(test.p) != test.inside {
+			t.Errorf("Union should contain %v: %v", test.p, test.inside)
+		}
+		if d, expected := u.SignedDistance(test.p), math.Min(ring.SignedDistance(test.p), block.SignedDistance(test.p)); d != expected {
+			t.Errorf("Signed distance at %v should be the least of the shapes, %v, not %v", test.p, expected, d)
+		}
+		if m := u.Merge(); m.Contains(test.p) != test.inside {
+			t.Errorf("Merged union should contain %v: %v", test.p, test.inside)
+		}
+		if s := (Stencil{Shape: u, Width: 16, Height: 16}); s.Within(int(test.p.X), int(test.p.Y)) != test.inside {
+			t.Errorf("Stencil of the union should contain pixel %v: %v", test.p, test.inside)
+		}
+	}
+
+	if min, max := u.Bounds(); min != (Vec{0, 0}) || max != (Vec{15, 15}) {
+		t.Errorf("Bounds should be (0, 0)-(15, 15), not %v-%v", min, max)
+	}
+	if d := (Union{}).SignedDistance(Vec{}); !math.IsInf(d, 1) {
+		t.Errorf("Empty union should be infinitely far away, not %v", d)
+	}
+}
@@ -0,0 +1,58 @@
+// Package vector models shapes as closed contours of line and Bézier segments, with exact
+// distance and containment queries so that Signed-Distance-Fields can be computed from them
+// at any resolution without rasterisation error.
+//
+// Shapes use the same coordinate system as images, with y pointing down. The centre of the
+// pixel at (x, y) lies at (x+0.5, y+0.5).
+package vector
+
+import "math"
+
+// Vec is a 2D point or vector
+type Vec struct {
+	X, Y float64
+}
+
+// Add returns v+u
+func (v Vec) Add(u Vec) Vec {
+	return Vec{v.X + u.X, v.Y + u.Y}
+}
+
+// Sub returns v-u
+func (v Vec) Sub(u Vec) Vec {
+	return Vec{v.X - u.X, v.Y - u.Y}
+}
+
+// Scale returns v scaled by s
+func (v Vec) Scale(s float64) Vec {
+	return Vec{v.X * s, v.Y * s}
+}
+
+// Dot returns the dot product of v and u
+func (v Vec) Dot(u Vec) float64 {
+	return v.X*u.X + v.Y*u.Y
+}
+
+// Cross returns the z-component of the cross product of v and u
+func (v Vec) Cross(u Vec) float64 {
+	return v.X*u.Y - v.Y*u.X
+}
+
+// Len returns the length of v
+func (v Vec) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Normalize returns v scaled to unit length, or the zero vector if v has no length
+func (v Vec) Normalize() Vec {
+	l := v.Len()
+	if l == 0 {
+		return Vec{}
+	}
+	return Vec{v.X / l, v.Y / l}
+}
+
+// Lerp returns the linear interpolation from v to u, weighted by t in range [0, 1]
+func (v Vec) Lerp(u Vec, t float64) Vec {
+	return Vec{v.X + (u.X-v.X)*t, v.Y + (u.Y-v.Y)*t}
+}